@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command controller runs the gcppubsub reconcilers (Topic, PullSubscription, GcpPubSubSource,
+// CloudStorageSource) against a single controller-runtime manager.
+//
+// This is a deliberate departure from the knative.dev/pkg/controller + injection + genreconciler
+// stack the rest of this repository's reconcilers are built on: the four reconcilers here were
+// written directly against sigs.k8s.io/controller-runtime instead. That divergence is called out
+// here, rather than left silent, so it gets the maintainers' explicit sign-off before this
+// entrypoint is wired into a release - not because controller-runtime is the wrong choice, but
+// because a second reconciliation stack in the same repository is a real cost (two sets of
+// leader-election/metrics/logging conventions, two things a new contributor has to learn) that
+// should be a decision, not an accident of how this package happened to be written.
+package main
+
+import (
+	"os"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/cloudstoragesource"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/gcppubsubsource"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/pullsubscription"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/topic"
+)
+
+func main() {
+	log.SetLogger(log.ZapLogger(false))
+	entryLog := log.Log.WithName("entrypoint")
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		entryLog.Error(err, "getting kubeconfig")
+		os.Exit(1)
+	}
+
+	mgr, err := manager.New(cfg, manager.Options{})
+	if err != nil {
+		entryLog.Error(err, "creating manager")
+		os.Exit(1)
+	}
+
+	if err := sourcesv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		entryLog.Error(err, "registering sources/v1alpha1 scheme")
+		os.Exit(1)
+	}
+
+	topicReconciler := &topic.Reconciler{
+		Client:             mgr.GetClient(),
+		CreatePubSubClient: topic.DefaultCreatePubSubClient,
+	}
+	if err := topicReconciler.SetupWithManager(mgr); err != nil {
+		entryLog.Error(err, "setting up topic controller")
+		os.Exit(1)
+	}
+
+	pullSubscriptionReconciler := &pullsubscription.Reconciler{
+		Client:                 mgr.GetClient(),
+		CreatePubSubClient:     pullsubscription.DefaultCreatePubSubClient,
+		ResolveObjectReference: pullsubscription.DefaultResolveObjectReference,
+	}
+	if err := pullSubscriptionReconciler.SetupWithManager(mgr); err != nil {
+		entryLog.Error(err, "setting up pullsubscription controller")
+		os.Exit(1)
+	}
+
+	gcpPubSubSourceReconciler := &gcppubsubsource.Reconciler{Client: mgr.GetClient()}
+	if err := gcpPubSubSourceReconciler.SetupWithManager(mgr); err != nil {
+		entryLog.Error(err, "setting up gcppubsubsource controller")
+		os.Exit(1)
+	}
+
+	cloudStorageSourceReconciler := &cloudstoragesource.Reconciler{
+		Client:          mgr.GetClient(),
+		CreateGCSClient: cloudstoragesource.DefaultCreateGCSClient,
+	}
+	if err := cloudStorageSourceReconciler.SetupWithManager(mgr); err != nil {
+		entryLog.Error(err, "setting up cloudstoragesource controller")
+		os.Exit(1)
+	}
+
+	entryLog.Info("starting manager")
+	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+		entryLog.Error(err, "running manager")
+		os.Exit(1)
+	}
+}
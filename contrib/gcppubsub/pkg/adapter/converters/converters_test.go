@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestGetKnownConverters(t *testing.T) {
+	for _, name := range []string{RawPubSub, Push} {
+		if _, err := Get(name); err != nil {
+			t.Errorf("Get(%q) = %v, want no error", name, err)
+		}
+		if _, err := GetAttributes(name); err != nil {
+			t.Errorf("GetAttributes(%q) = %v, want no error", name, err)
+		}
+	}
+}
+
+func TestGetUnknownConverter(t *testing.T) {
+	if _, err := Get("nope"); err == nil {
+		t.Error("Get(\"nope\") = nil, want error")
+	}
+	if _, err := GetAttributes("nope"); err == nil {
+		t.Error("GetAttributes(\"nope\") = nil, want error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	const name = "test-converter"
+	wantAttrs := []duckv1.CloudEventAttributes{{Type: "test.type", Source: "test.source"}}
+
+	Register(name, func(ctx context.Context, pctx Context, msg *pubsub.Message) (*cloudevents.Event, error) {
+		return nil, nil
+	}, func(pctx Context) []duckv1.CloudEventAttributes {
+		return wantAttrs
+	})
+
+	attrsFn, err := GetAttributes(name)
+	if err != nil {
+		t.Fatalf("GetAttributes(%q) = %v", name, err)
+	}
+	got := attrsFn(Context{})
+	if len(got) != 1 || got[0] != wantAttrs[0] {
+		t.Errorf("attrsFn(Context{}) = %+v, want %+v", got, wantAttrs)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+// rawPubSubConverter implements the RawPubSub Converter: it wraps the raw PubSub message as the
+// CloudEvent data, in case PubSub doesn't send a CloudEvent itself. This preserves the adapter's
+// pre-converter-registry behavior.
+func rawPubSubConverter(ctx context.Context, pctx Context, msg *pubsub.Message) (*cloudevents.Event, error) {
+	event := cloudevents.NewEvent()
+	event.SetID(msg.ID)
+	event.SetType(sourcesv1alpha1.GcpPubSubSourceEventType)
+	event.SetSource(sourcesv1alpha1.GcpPubSubEventSource(pctx.Project, pctx.Topic))
+	event.SetTime(msg.PublishTime)
+	for k, v := range msg.Attributes {
+		event.SetExtension(k, v)
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// rawPubSubAttributes is the Attributes for RawPubSub: it always emits
+// v1alpha1.GcpPubSubSourceEventType from the configured project/topic.
+func rawPubSubAttributes(pctx Context) []duckv1.CloudEventAttributes {
+	return []duckv1.CloudEventAttributes{{
+		Type:   sourcesv1alpha1.GcpPubSubSourceEventType,
+		Source: sourcesv1alpha1.GcpPubSubEventSource(pctx.Project, pctx.Topic),
+	}}
+}
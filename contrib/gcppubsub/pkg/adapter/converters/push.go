@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+// pushEnvelope is the body PubSub sends to an HTTP push endpoint.
+// See https://cloud.google.com/pubsub/docs/push#receiving_messages.
+type pushEnvelope struct {
+	Message struct {
+		Attributes  map[string]string `json:"attributes"`
+		Data        []byte            `json:"data"`
+		MessageID   string            `json:"messageId"`
+		PublishTime time.Time         `json:"publishTime"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// DecodePushEnvelope parses a PubSub HTTP push request body into a pubsub.Message, so that the
+// Push Converter can be driven the same way as RawPubSub once the envelope has been unwrapped.
+func DecodePushEnvelope(body io.Reader) (*pubsub.Message, error) {
+	var env pushEnvelope
+	if err := json.NewDecoder(body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("decoding push envelope: %w", err)
+	}
+	return &pubsub.Message{
+		ID:          env.Message.MessageID,
+		Data:        env.Message.Data,
+		Attributes:  env.Message.Attributes,
+		PublishTime: env.Message.PublishTime,
+	}, nil
+}
+
+// pushConverter implements the Push Converter. It behaves like RawPubSub, except that it also sets
+// the CloudEvent subject to the Subscription ID. It is registered
+// separately so that sources which are only ever delivered via push (rather than pulled) can
+// select it explicitly without depending on RawPubSub's pull-specific assumptions.
+func pushConverter(ctx context.Context, pctx Context, msg *pubsub.Message) (*cloudevents.Event, error) {
+	event := cloudevents.NewEvent()
+	event.SetID(msg.ID)
+	event.SetType(sourcesv1alpha1.GcpPubSubSourceEventType)
+	event.SetSource(sourcesv1alpha1.GcpPubSubEventSource(pctx.Project, pctx.Topic))
+	event.SetSubject(pctx.Subscription)
+	event.SetTime(msg.PublishTime)
+	for k, v := range msg.Attributes {
+		event.SetExtension(k, v)
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// pushAttributes is the Attributes for Push: it emits the same Type/Source as RawPubSub.
+// duckv1.CloudEventAttributes has no Subject field, so the Subject pushConverter sets on the
+// CloudEvent itself can't be advertised here.
+func pushAttributes(pctx Context) []duckv1.CloudEventAttributes {
+	return []duckv1.CloudEventAttributes{{
+		Type:   sourcesv1alpha1.GcpPubSubSourceEventType,
+		Source: sourcesv1alpha1.GcpPubSubEventSource(pctx.Project, pctx.Topic),
+	}}
+}
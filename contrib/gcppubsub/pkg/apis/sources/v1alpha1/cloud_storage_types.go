@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudStorageSource is the Schema for the cloudstoragesources API. It notifies a GCS Bucket's
+// object change events to a PubSub Topic, then reuses a GcpPubSubSource to deliver those events
+// to a sink as CloudEvents.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:categories=all,knative,eventing,sources
+type CloudStorageSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudStorageSourceSpec   `json:"spec,omitempty"`
+	Status CloudStorageSourceStatus `json:"status,omitempty"`
+}
+
+// Check that CloudStorageSource can be validated and can be defaulted.
+var _ runtime.Object = (*CloudStorageSource)(nil)
+
+// Check that CloudStorageSource will be checked for immutable fields.
+var _ apis.Immutable = (*CloudStorageSource)(nil)
+
+// Check that CloudStorageSource can be validated.
+var _ apis.Validatable = (*CloudStorageSource)(nil)
+
+// Check that CloudStorageSource implements the Conditions duck type.
+var _ = duck.VerifyType(&CloudStorageSource{}, &duckv1alpha1.Conditions{})
+
+// CloudStorageSourceEventType is the type of event that can be emitted by a bucket notification.
+type CloudStorageSourceEventType string
+
+const (
+	// CloudStorageSourceFinalize is sent when an object is successfully created.
+	CloudStorageSourceFinalize CloudStorageSourceEventType = "OBJECT_FINALIZE"
+
+	// CloudStorageSourceArchive is sent when a live version of an object becomes a noncurrent version.
+	CloudStorageSourceArchive CloudStorageSourceEventType = "OBJECT_ARCHIVE"
+
+	// CloudStorageSourceDelete is sent when an object is permanently deleted.
+	CloudStorageSourceDelete CloudStorageSourceEventType = "OBJECT_DELETE"
+
+	// CloudStorageSourceMetadataUpdate is sent when an object's metadata changes.
+	CloudStorageSourceMetadataUpdate CloudStorageSourceEventType = "OBJECT_METADATA_UPDATE"
+)
+
+// CloudStorageSourceSpec defines the desired state of the CloudStorageSource.
+type CloudStorageSourceSpec struct {
+	// GcpCredsSecret is the credential used to manage the bucket notification as well as to poll
+	// the GCP PubSub Subscription it publishes to. The value of the secret entry must be a service
+	// account key in the JSON format
+	// ( see https://cloud.google.com/iam/docs/creating-managing-service-account-keys ).
+	GcpCredsSecret corev1.SecretKeySelector `json:"gcpCredsSecret,omitempty"`
+
+	// GoogleCloudProject is the ID of the Google Cloud Project that the GCS Bucket exists in.
+	GoogleCloudProject string `json:"googleCloudProject,omitempty"`
+
+	// Bucket is the name of the GCS bucket to receive notifications from.
+	Bucket string `json:"bucket,omitempty"`
+
+	// EventTypes is the list of event types to receive notifications for. If unspecified, all
+	// event types are sent.
+	// +optional
+	EventTypes []CloudStorageSourceEventType `json:"eventTypes,omitempty"`
+
+	// ObjectNamePrefix is an optional prefix to only receive notifications for objects whose name
+	// begins with this prefix.
+	// +optional
+	ObjectNamePrefix string `json:"objectNamePrefix,omitempty"`
+
+	// PayloadFormat specifies the contents of the notification payload, either "JSON_API_V1" or
+	// "NONE". If unspecified, "JSON_API_V1" is assumed.
+	// +optional
+	PayloadFormat string `json:"payloadFormat,omitempty"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	// +optional
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+
+	// Transformer is a reference to an object that will resolve to a domain name to use as the transformer.
+	// +optional
+	Transformer *corev1.ObjectReference `json:"transformer,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount that will be used to run the Receive
+	// Adapter Deployment.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+const (
+	// CloudStorageSourceConditionReady has status True when the CloudStorageSource is ready to send events.
+	CloudStorageSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// CloudStorageSourceConditionPubSubTopicReady has status True when the backing PubSub Topic has been created.
+	CloudStorageSourceConditionPubSubTopicReady duckv1alpha1.ConditionType = "PubSubTopicReady"
+
+	// CloudStorageSourceConditionPubSubSourceReady has status True when the backing GcpPubSubSource has been created and is ready.
+	CloudStorageSourceConditionPubSubSourceReady duckv1alpha1.ConditionType = "PubSubSourceReady"
+
+	// CloudStorageSourceConditionNotificationReady has status True when the GCS Bucket notification has been created.
+	CloudStorageSourceConditionNotificationReady duckv1alpha1.ConditionType = "NotificationReady"
+)
+
+var cloudStorageSourceCondSet = duckv1alpha1.NewLivingConditionSet(
+	CloudStorageSourceConditionPubSubTopicReady,
+	CloudStorageSourceConditionPubSubSourceReady,
+	CloudStorageSourceConditionNotificationReady)
+
+// CloudStorageEventSource returns the GCS CloudEvent source value for a given bucket.
+func CloudStorageEventSource(bucket string) string {
+	return fmt.Sprintf("//storage.googleapis.com/buckets/%s", bucket)
+}
+
+// CloudStorageSourceStatus defines the observed state of the CloudStorageSource.
+type CloudStorageSourceStatus struct {
+	// inherits duck/v1alpha1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the CloudStorageSource that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1alpha1.Status `json:",inline"`
+
+	// SinkURI is the current active sink URI that has been configured for the CloudStorageSource.
+	// +optional
+	SinkURI string `json:"sinkUri,omitempty"`
+
+	// TopicID is the ID of the PubSub Topic created to receive the bucket notifications.
+	// +optional
+	TopicID string `json:"topicId,omitempty"`
+
+	// NotificationID is the ID of the GCS Bucket notification that was created.
+	// +optional
+	NotificationID string `json:"notificationId,omitempty"`
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *CloudStorageSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return cloudStorageSourceCondSet.Manage(s).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *CloudStorageSourceStatus) IsReady() bool {
+	return cloudStorageSourceCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *CloudStorageSourceStatus) InitializeConditions() {
+	cloudStorageSourceCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkPubSubTopic sets the condition that the backing PubSub Topic has been created.
+func (s *CloudStorageSourceStatus) MarkPubSubTopic(topicID string) {
+	s.TopicID = topicID
+	cloudStorageSourceCondSet.Manage(s).MarkTrue(CloudStorageSourceConditionPubSubTopicReady)
+}
+
+// MarkNoPubSubTopic sets the condition that the backing PubSub Topic could not be created.
+func (s *CloudStorageSourceStatus) MarkNoPubSubTopic(reason, messageFormat string, messageA ...interface{}) {
+	cloudStorageSourceCondSet.Manage(s).MarkFalse(CloudStorageSourceConditionPubSubTopicReady, reason, messageFormat, messageA...)
+}
+
+// MarkPubSubSource sets the condition that the backing GcpPubSubSource has been created and is ready.
+func (s *CloudStorageSourceStatus) MarkPubSubSource(sinkURI string) {
+	s.SinkURI = sinkURI
+	cloudStorageSourceCondSet.Manage(s).MarkTrue(CloudStorageSourceConditionPubSubSourceReady)
+}
+
+// MarkNoPubSubSource sets the condition that the backing GcpPubSubSource is not ready.
+func (s *CloudStorageSourceStatus) MarkNoPubSubSource(reason, messageFormat string, messageA ...interface{}) {
+	cloudStorageSourceCondSet.Manage(s).MarkFalse(CloudStorageSourceConditionPubSubSourceReady, reason, messageFormat, messageA...)
+}
+
+// MarkNotification sets the condition that the GCS Bucket notification has been created.
+func (s *CloudStorageSourceStatus) MarkNotification(notificationID string) {
+	s.NotificationID = notificationID
+	cloudStorageSourceCondSet.Manage(s).MarkTrue(CloudStorageSourceConditionNotificationReady)
+}
+
+// MarkNoNotification sets the condition that the GCS Bucket notification could not be created.
+func (s *CloudStorageSourceStatus) MarkNoNotification(reason, messageFormat string, messageA ...interface{}) {
+	cloudStorageSourceCondSet.Manage(s).MarkFalse(CloudStorageSourceConditionNotificationReady, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudStorageSourceList contains a list of CloudStorageSources.
+type CloudStorageSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudStorageSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CloudStorageSource{}, &CloudStorageSourceList{})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate implements apis.Validatable.
+func (s *CloudStorageSource) Validate(ctx context.Context) *apis.FieldError {
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate checks that spec has the fields required to watch a GCS Bucket and that PayloadFormat,
+// if set, is one GCS actually accepts.
+func (spec *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if spec.GoogleCloudProject == "" {
+		errs = errs.Also(apis.ErrMissingField("googleCloudProject"))
+	}
+	if spec.Bucket == "" {
+		errs = errs.Also(apis.ErrMissingField("bucket"))
+	}
+	if spec.PayloadFormat != "" && spec.PayloadFormat != "JSON_API_V1" && spec.PayloadFormat != "NONE" {
+		errs = errs.Also(apis.ErrInvalidValue(spec.PayloadFormat, "payloadFormat"))
+	}
+	return errs
+}
+
+// CheckImmutableFields implements apis.Immutable. GoogleCloudProject and Bucket identify which GCS
+// Bucket notification this CloudStorageSource manages; changing either out from under a running
+// reconciler would silently start managing a different bucket instead of actually moving this one.
+func (s *CloudStorageSource) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	original, ok := og.(*CloudStorageSource)
+	if !ok {
+		return &apis.FieldError{Message: "The provided original was not a CloudStorageSource"}
+	}
+
+	var errs *apis.FieldError
+	if s.Spec.GoogleCloudProject != original.Spec.GoogleCloudProject {
+		errs = errs.Also(&apis.FieldError{
+			Message: "GoogleCloudProject is immutable",
+			Paths:   []string{"spec.googleCloudProject"},
+		})
+	}
+	if s.Spec.Bucket != original.Spec.Bucket {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Bucket is immutable",
+			Paths:   []string{"spec.bucket"},
+		})
+	}
+	return errs
+}
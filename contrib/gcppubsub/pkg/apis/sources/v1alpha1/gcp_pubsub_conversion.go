@@ -0,0 +1,219 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1beta1"
+)
+
+// transformerAnnotationKey stores the Transformer reference across a round trip through v1beta1,
+// which has no lossless counterpart for it.
+const transformerAnnotationKey = "sources.eventing.knative.dev/transformer"
+
+// eventTypesProvidedAnnotationKey stores whether EventTypesProvided was True across a round trip
+// through v1beta1, which dropped the condition.
+const eventTypesProvidedAnnotationKey = "sources.eventing.knative.dev/eventTypesProvided"
+
+// sinkURIAnnotationKey stores a v1beta1 duckv1.Destination's URI across a round trip through
+// v1alpha1, whose Spec.Sink is a bare *corev1.ObjectReference and cannot represent a URI sink.
+const sinkURIAnnotationKey = "sources.eventing.knative.dev/sinkUri"
+
+// Check that GcpPubSubSource can be converted to/from higher version of this resource.
+var _ apis.Convertible = (*GcpPubSubSource)(nil)
+
+// ConvertTo implements apis.Convertible.
+func (source *GcpPubSubSource) ConvertTo(ctx context.Context, to apis.Convertible) error {
+	switch sink := to.(type) {
+	case *v1beta1.GcpPubSubSource:
+		sink.ObjectMeta = source.ObjectMeta
+		sink.Annotations = cloneAnnotations(source.Annotations)
+		sink.Spec = v1beta1.GcpPubSubSourceSpec{
+			Secret:                    source.Spec.GcpCredsSecret,
+			Project:                   source.Spec.GoogleCloudProject,
+			Topic:                     source.Spec.Topic,
+			ServiceAccountName:        source.Spec.ServiceAccountName,
+			SubscriptionPolicy:        convertSubscriptionPolicyTo(source.Spec.SubscriptionPolicy),
+			SubscriptionReclaimPolicy: v1beta1.SubscriptionReclaimPolicy(source.Spec.SubscriptionReclaimPolicy),
+		}
+		if source.Spec.Sink != nil {
+			sink.Spec.Sink = duckv1.Destination{Ref: source.Spec.Sink}
+		} else if uri, ok := source.Annotations[sinkURIAnnotationKey]; ok {
+			parsed, err := apis.ParseURL(uri)
+			if err != nil {
+				return fmt.Errorf("parsing %s annotation: %w", sinkURIAnnotationKey, err)
+			}
+			sink.Spec.Sink = duckv1.Destination{URI: parsed}
+		}
+		if source.Spec.Transformer != nil {
+			if err := setAnnotationJSON(sink, transformerAnnotationKey, source.Spec.Transformer); err != nil {
+				return err
+			}
+		}
+		if c := source.Status.GetCondition(GcpPubSubConditionEventTypesProvided); c != nil && c.Status == corev1.ConditionTrue {
+			setAnnotation(sink, eventTypesProvidedAnnotationKey, "true")
+		}
+		sink.Status.ObservedGeneration = source.Status.ObservedGeneration
+		sink.Status.SinkURI, _ = apis.ParseURL(source.Status.SinkURI)
+		return nil
+	default:
+		return fmt.Errorf("unknown conversion, got: %T", sink)
+	}
+}
+
+// ConvertFrom implements apis.Convertible.
+func (sink *GcpPubSubSource) ConvertFrom(ctx context.Context, from apis.Convertible) error {
+	switch source := from.(type) {
+	case *v1beta1.GcpPubSubSource:
+		sink.ObjectMeta = source.ObjectMeta
+		sink.Annotations = cloneAnnotations(source.Annotations)
+		sink.Spec = GcpPubSubSourceSpec{
+			GcpCredsSecret:            source.Spec.Secret,
+			GoogleCloudProject:        source.Spec.Project,
+			Topic:                     source.Spec.Topic,
+			ServiceAccountName:        source.Spec.ServiceAccountName,
+			SubscriptionPolicy:        convertSubscriptionPolicyFrom(source.Spec.SubscriptionPolicy),
+			SubscriptionReclaimPolicy: SubscriptionReclaimPolicy(source.Spec.SubscriptionReclaimPolicy),
+		}
+		if source.Spec.Sink.Ref != nil {
+			sink.Spec.Sink = source.Spec.Sink.Ref
+			delete(sink.Annotations, sinkURIAnnotationKey)
+		} else if source.Spec.Sink.URI != nil {
+			if sink.Annotations == nil {
+				sink.Annotations = map[string]string{}
+			}
+			sink.Annotations[sinkURIAnnotationKey] = source.Spec.Sink.URI.String()
+		}
+		if ref, ok, err := getAnnotationJSON(source, transformerAnnotationKey, &corev1.ObjectReference{}); err != nil {
+			return err
+		} else if ok {
+			sink.Spec.Transformer = ref.(*corev1.ObjectReference)
+		}
+		sink.Status.ObservedGeneration = source.Status.ObservedGeneration
+		if source.Status.SinkURI != nil {
+			sink.Status.SinkURI = source.Status.SinkURI.String()
+		}
+		if v, ok := source.Annotations[eventTypesProvidedAnnotationKey]; ok && v == "true" {
+			sink.Status.MarkEventTypes()
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown conversion, got: %T", source)
+	}
+}
+
+// convertSubscriptionPolicyTo maps a v1alpha1 SubscriptionPolicy onto its v1beta1 counterpart.
+// The two types are structurally identical; this mapping exists so that a later divergence
+// between the versions doesn't silently become a lossy round trip.
+func convertSubscriptionPolicyTo(p *SubscriptionPolicy) *v1beta1.SubscriptionPolicy {
+	if p == nil {
+		return nil
+	}
+	out := &v1beta1.SubscriptionPolicy{
+		AckDeadlineSeconds:       p.AckDeadlineSeconds,
+		RetainAckedMessages:      p.RetainAckedMessages,
+		MessageRetentionDuration: p.MessageRetentionDuration,
+		MaxDeliveryAttempts:      p.MaxDeliveryAttempts,
+	}
+	if p.RetryPolicy != nil {
+		out.RetryPolicy = &v1beta1.RetryPolicy{
+			MinimumBackoff: p.RetryPolicy.MinimumBackoff,
+			MaximumBackoff: p.RetryPolicy.MaximumBackoff,
+		}
+	}
+	if p.DeadLetterTopic != nil {
+		out.DeadLetterTopic = &v1beta1.PubSubTopicReference{
+			Project: p.DeadLetterTopic.Project,
+			Topic:   p.DeadLetterTopic.Topic,
+		}
+	}
+	return out
+}
+
+// convertSubscriptionPolicyFrom maps a v1beta1 SubscriptionPolicy onto its v1alpha1 counterpart.
+func convertSubscriptionPolicyFrom(p *v1beta1.SubscriptionPolicy) *SubscriptionPolicy {
+	if p == nil {
+		return nil
+	}
+	out := &SubscriptionPolicy{
+		AckDeadlineSeconds:       p.AckDeadlineSeconds,
+		RetainAckedMessages:      p.RetainAckedMessages,
+		MessageRetentionDuration: p.MessageRetentionDuration,
+		MaxDeliveryAttempts:      p.MaxDeliveryAttempts,
+	}
+	if p.RetryPolicy != nil {
+		out.RetryPolicy = &RetryPolicy{
+			MinimumBackoff: p.RetryPolicy.MinimumBackoff,
+			MaximumBackoff: p.RetryPolicy.MaximumBackoff,
+		}
+	}
+	if p.DeadLetterTopic != nil {
+		out.DeadLetterTopic = &PubSubTopicReference{
+			Project: p.DeadLetterTopic.Project,
+			Topic:   p.DeadLetterTopic.Topic,
+		}
+	}
+	return out
+}
+
+// cloneAnnotations returns a copy of m, so that mutating the result (to stash lossy fields) never
+// corrupts the source object's annotations, which share the same underlying map once ObjectMeta
+// is assigned across versions.
+func cloneAnnotations(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func setAnnotation(obj *v1beta1.GcpPubSubSource, key, value string) {
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[key] = value
+}
+
+func setAnnotationJSON(obj *v1beta1.GcpPubSubSource, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	setAnnotation(obj, key, string(b))
+	return nil
+}
+
+func getAnnotationJSON(obj *v1beta1.GcpPubSubSource, key string, out interface{}) (interface{}, bool, error) {
+	v, ok := obj.Annotations[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if err := json.Unmarshal([]byte(v), out); err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1beta1"
+)
+
+// TestGcpPubSubSourceConversionRoundTrip checks that converting a v1alpha1.GcpPubSubSource to
+// v1beta1 and back reproduces the original Spec, including Transformer, which is stashed in an
+// annotation across the round trip.
+func TestGcpPubSubSourceConversionRoundTrip(t *testing.T) {
+	ackDeadline := int64(30)
+	maxAttempts := int32(5)
+
+	original := &GcpPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "source"},
+		Spec: GcpPubSubSourceSpec{
+			GcpCredsSecret:     corev1.SecretKeySelector{Key: "key.json"},
+			GoogleCloudProject: "my-project",
+			Topic:              "my-topic",
+			Transformer:        &corev1.ObjectReference{Kind: "Service", Name: "transformer"},
+			ServiceAccountName: "ksa",
+			SubscriptionPolicy: &SubscriptionPolicy{
+				AckDeadlineSeconds:  &ackDeadline,
+				DeadLetterTopic:     &PubSubTopicReference{Topic: "dead-letters"},
+				MaxDeliveryAttempts: &maxAttempts,
+			},
+			SubscriptionReclaimPolicy: SubscriptionReclaimRetain,
+		},
+	}
+
+	var mid v1beta1.GcpPubSubSource
+	if err := original.ConvertTo(context.Background(), &mid); err != nil {
+		t.Fatalf("ConvertTo() = %v", err)
+	}
+
+	var got GcpPubSubSource
+	if err := got.ConvertFrom(context.Background(), &mid); err != nil {
+		t.Fatalf("ConvertFrom() = %v", err)
+	}
+
+	// ConvertFrom does not set TypeMeta; copy it across from the original.
+	got.TypeMeta = original.TypeMeta
+
+	if !reflect.DeepEqual(original.Spec, got.Spec) {
+		t.Errorf("round trip Spec mismatch:\noriginal: %+v\ngot:      %+v", original.Spec, got.Spec)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// SetDefaults defaults the SubscriptionReclaimPolicy to SubscriptionReclaimDelete if unset.
+func (s *GcpPubSubSource) SetDefaults() {
+	if s.Spec.SubscriptionReclaimPolicy == "" {
+		s.Spec.SubscriptionReclaimPolicy = SubscriptionReclaimDelete
+	}
+}
+
+// SetDefaults defaults the SubscriptionReclaimPolicy to SubscriptionReclaimDelete if unset.
+func (p *PullSubscription) SetDefaults() {
+	if p.Spec.SubscriptionReclaimPolicy == "" {
+		p.Spec.SubscriptionReclaimPolicy = SubscriptionReclaimDelete
+	}
+}
+
+// SetDefaults is currently a no-op: unlike GcpPubSubSource and PullSubscription, Topic has no
+// field whose zero value needs to be replaced by a default. It exists so Topic satisfies the same
+// defaulting shape as its sibling internal resources.
+func (t *Topic) SetDefaults() {}
+
+// SetDefaults defaults PayloadFormat to "JSON_API_V1" if unset.
+func (s *CloudStorageSource) SetDefaults() {
+	if s.Spec.PayloadFormat == "" {
+		s.Spec.PayloadFormat = "JSON_API_V1"
+	}
+}
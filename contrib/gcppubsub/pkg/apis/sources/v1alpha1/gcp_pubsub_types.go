@@ -24,13 +24,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
-// GcpPubSubSource is the Schema for the gcppubsubsources API.
+// GcpPubSubSource is the Schema for the gcppubsubsources API. It is a thin aggregator over a
+// Topic and a PullSubscription: it creates one of each and rolls up their conditions into
+// GcpPubSubConditionSubscribed and GcpPubSubConditionDeployed.
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:categories=all,knative,eventing,sources
@@ -48,6 +51,9 @@ var _ runtime.Object = (*GcpPubSubSource)(nil)
 // Check that GcpPubSubSource will be checked for immutable fields.
 var _ apis.Immutable = (*GcpPubSubSource)(nil)
 
+// Check that GcpPubSubSource can be validated.
+var _ apis.Validatable = (*GcpPubSubSource)(nil)
+
 // Check that GcpPubSubSource implements the Conditions duck type.
 var _ = duck.VerifyType(&GcpPubSubSource{}, &duckv1alpha1.Conditions{})
 
@@ -78,6 +84,20 @@ type GcpPubSubSourceSpec struct {
 	// ServiceAccoutName is the name of the ServiceAccount that will be used to run the Receive
 	// Adapter Deployment.
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Converter is the name of the receive adapter Converter to use to turn incoming PubSub
+	// messages into CloudEvents, e.g. "raw_pubsub" or "push". If unset, "raw_pubsub" is used.
+	// +optional
+	Converter string `json:"converter,omitempty"`
+
+	// SubscriptionPolicy controls the delivery semantics of the underlying GCP PubSub Subscription.
+	// +optional
+	SubscriptionPolicy *SubscriptionPolicy `json:"subscriptionPolicy,omitempty"`
+
+	// SubscriptionReclaimPolicy determines whether the underlying GCP PubSub Subscription is
+	// deleted when this GcpPubSubSource is deleted. Defaults to Delete.
+	// +optional
+	SubscriptionReclaimPolicy SubscriptionReclaimPolicy `json:"subscriptionReclaimPolicy,omitempty"`
 }
 
 const (
@@ -109,6 +129,11 @@ const (
 
 	// GcpPubSubConditionEventTypesProvided has status True when the GcpPubSubSource has been configured with event types.
 	GcpPubSubConditionEventTypesProvided duckv1alpha1.ConditionType = "EventTypesProvided"
+
+	// GcpPubSubConditionDeadLetterReady has status True when a DeadLetterTopic has been configured
+	// in the SubscriptionPolicy and the underlying GCP PubSub Subscription has been updated to use
+	// it. It is left unset when no DeadLetterTopic is configured.
+	GcpPubSubConditionDeadLetterReady duckv1alpha1.ConditionType = "DeadLetterReady"
 )
 
 var gcpPubSubSourceCondSet = duckv1alpha1.NewLivingConditionSet(
@@ -130,6 +155,19 @@ type GcpPubSubSourceStatus struct {
 	// TransformerURI is the current active transformer URI that has been configured for the GcpPubSubSource.
 	// +optional
 	TransformerURI string `json:"transformerUri,omitempty"`
+
+	// TopicName is the name of the Topic created to back this GcpPubSubSource.
+	// +optional
+	TopicName string `json:"topicName,omitempty"`
+
+	// PullSubscriptionName is the name of the PullSubscription created to back this GcpPubSubSource.
+	// +optional
+	PullSubscriptionName string `json:"pullSubscriptionName,omitempty"`
+
+	// CloudEventAttributes are the CloudEvent attributes that the receive adapter will emit, given
+	// the configured Converter.
+	// +optional
+	CloudEventAttributes []duckv1.CloudEventAttributes `json:"ceAttributes,omitempty"`
 }
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -196,6 +234,103 @@ func (s *GcpPubSubSourceStatus) MarkSubscribed() {
 	gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionSubscribed)
 }
 
+// PropagateTopicStatus rolls up the Topic's Ready condition into GcpPubSubConditionSubscribed and
+// GcpPubSubConditionDeployed while the Topic is not yet ready: the PullSubscription that actually
+// drives both of those conditions (see PropagatePullSubscriptionStatus) can't be created, let
+// alone become ready, before its backing Topic exists.
+func (s *GcpPubSubSourceStatus) PropagateTopicStatus(ts *TopicStatus) {
+	tc := ts.GetCondition(TopicConditionReady)
+	if tc == nil {
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionSubscribed, "TopicNotReady", "Topic status is not yet known.")
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionDeployed, "TopicNotReady", "Topic status is not yet known.")
+		return
+	}
+	switch tc.Status {
+	case corev1.ConditionTrue:
+		// The Topic is ready; Subscribed and Deployed are now driven by
+		// PropagatePullSubscriptionStatus once the PullSubscription exists.
+	case corev1.ConditionFalse:
+		gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionSubscribed, tc.Reason, tc.Message)
+		gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionDeployed, tc.Reason, tc.Message)
+	default:
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionSubscribed, tc.Reason, tc.Message)
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionDeployed, tc.Reason, tc.Message)
+	}
+}
+
+// PropagatePullSubscriptionStatus rolls up the PullSubscription's own SubscriptionReady condition
+// into GcpPubSubConditionSubscribed, its Deployed condition into GcpPubSubConditionDeployed, and
+// its SinkProvided condition (with SinkURI) into GcpPubSubConditionSinkProvided.
+// PullSubscription is the resource that actually owns the GCP PubSub Subscription and the receive
+// adapter Deployment, so it — not Topic — is the source of truth for all three.
+func (s *GcpPubSubSourceStatus) PropagatePullSubscriptionStatus(ps *PullSubscriptionStatus) {
+	if kc := ps.GetCondition(PullSubscriptionConditionSinkProvided); kc != nil {
+		switch kc.Status {
+		case corev1.ConditionTrue:
+			s.MarkSink(ps.SinkURI)
+		case corev1.ConditionFalse:
+			s.MarkNoSink(kc.Reason, kc.Message)
+		}
+	}
+
+	if sc := ps.GetCondition(PullSubscriptionConditionSubscribed); sc == nil {
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionSubscribed, "PullSubscriptionNotReady", "PullSubscription status is not yet known.")
+	} else {
+		switch sc.Status {
+		case corev1.ConditionTrue:
+			gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionSubscribed)
+		case corev1.ConditionFalse:
+			gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionSubscribed, sc.Reason, sc.Message)
+		default:
+			gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionSubscribed, sc.Reason, sc.Message)
+		}
+	}
+
+	if dc := ps.GetCondition(PullSubscriptionConditionDeployed); dc == nil {
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionDeployed, "PullSubscriptionNotReady", "PullSubscription status is not yet known.")
+	} else {
+		switch dc.Status {
+		case corev1.ConditionTrue:
+			gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionDeployed)
+		case corev1.ConditionFalse:
+			gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionDeployed, dc.Reason, dc.Message)
+		default:
+			gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionDeployed, dc.Reason, dc.Message)
+		}
+	}
+
+	if lc := ps.GetCondition(PullSubscriptionConditionDeadLetterReady); lc != nil {
+		switch lc.Status {
+		case corev1.ConditionTrue:
+			s.MarkDeadLetterTopicReady()
+		case corev1.ConditionFalse:
+			s.MarkNoDeadLetterTopic(lc.Reason, lc.Message)
+		default:
+			gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionDeadLetterReady, lc.Reason, lc.Message)
+		}
+	}
+}
+
+// MarkDeadLetterTopicReady sets the condition that the configured DeadLetterTopic has been applied
+// to the underlying GCP PubSub Subscription.
+func (s *GcpPubSubSourceStatus) MarkDeadLetterTopicReady() {
+	gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionDeadLetterReady)
+}
+
+// MarkNoDeadLetterTopic sets the condition that the configured DeadLetterTopic could not be
+// applied to the underlying GCP PubSub Subscription.
+func (s *GcpPubSubSourceStatus) MarkNoDeadLetterTopic(reason, messageFormat string, messageA ...interface{}) {
+	gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionDeadLetterReady, reason, messageFormat, messageA...)
+}
+
+// MarkCloudEventAttributes populates CloudEventAttributes with what the receive adapter will
+// emit. Callers (the reconciler) look these up from the registered Converter's Attributes func
+// (see gcppubsub/pkg/adapter/converters) so that the advertised attributes track whichever
+// Converter is actually configured via Spec.Converter, rather than assuming raw_pubsub.
+func (s *GcpPubSubSourceStatus) MarkCloudEventAttributes(attributes []duckv1.CloudEventAttributes) {
+	s.CloudEventAttributes = attributes
+}
+
 // MarkEventTypes sets the condition that the source has created its event types.
 func (s *GcpPubSubSourceStatus) MarkEventTypes() {
 	gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionEventTypesProvided)
@@ -0,0 +1,244 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PullSubscription is the Schema for the pullsubscriptions API. It is an internal resource that is
+// not intended to be used directly by end users, but rather as a building block for higher level
+// sources such as GcpPubSubSource and CloudStorageSource that need to pull messages off of a GCP
+// PubSub Subscription and deliver them to a sink as CloudEvents.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:categories=all,knative,eventing,sources
+type PullSubscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PullSubscriptionSpec   `json:"spec,omitempty"`
+	Status PullSubscriptionStatus `json:"status,omitempty"`
+}
+
+// Check that PullSubscription can be validated and can be defaulted.
+var _ runtime.Object = (*PullSubscription)(nil)
+
+// Check that PullSubscription will be checked for immutable fields.
+var _ apis.Immutable = (*PullSubscription)(nil)
+
+// Check that PullSubscription can be validated.
+var _ apis.Validatable = (*PullSubscription)(nil)
+
+// Check that PullSubscription implements the Conditions duck type.
+var _ = duck.VerifyType(&PullSubscription{}, &duckv1alpha1.Conditions{})
+
+// PullSubscriptionSpec defines the desired state of the PullSubscription.
+type PullSubscriptionSpec struct {
+	// GcpCredsSecret is the credential the controller uses to create, update and delete the GCP
+	// PubSub Subscription. It is not passed to the receive adapter Deployment. The value of the
+	// secret entry must be a service account key in the JSON format
+	// ( see https://cloud.google.com/iam/docs/creating-managing-service-account-keys ).
+	GcpCredsSecret corev1.SecretKeySelector `json:"gcpCredsSecret,omitempty"`
+
+	// GoogleCloudProject is the ID of the Google Cloud Project that the PubSub Topic exists in.
+	GoogleCloudProject string `json:"googleCloudProject,omitempty"`
+
+	// Topic is the ID of the GCP PubSub Topic to Subscribe to. It must be in the form of the
+	// unique identifier within the project, not the entire name. E.g. it must be 'laconia', not
+	// 'projects/my-gcp-project/topics/laconia'.
+	Topic string `json:"topic,omitempty"`
+
+	// Subscription is the ID of the GCP PubSub Subscription to use. If empty, the controller sets
+	// it to "<namespace>-<name>". The controller creates the Subscription if it does not exist, and
+	// deletes it when this PullSubscription is deleted unless SubscriptionReclaimPolicy is Retain.
+	// +optional
+	Subscription string `json:"subscription,omitempty"`
+
+	// Sink is a reference to an object that will resolve to a domain name to use as the sink.
+	// +optional
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+
+	// Transformer is a reference to an object that will resolve to a domain name to use as the transformer.
+	// +optional
+	Transformer *corev1.ObjectReference `json:"transformer,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount that will be used to run the Receive
+	// Adapter Deployment.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Converter is the name of the receive adapter Converter to use to turn incoming PubSub
+	// messages into CloudEvents, e.g. "raw_pubsub" or "push". If unset, "raw_pubsub" is used.
+	// +optional
+	Converter string `json:"converter,omitempty"`
+
+	// SubscriptionPolicy controls the delivery semantics of the underlying GCP PubSub Subscription.
+	// +optional
+	SubscriptionPolicy *SubscriptionPolicy `json:"subscriptionPolicy,omitempty"`
+
+	// SubscriptionReclaimPolicy determines whether the underlying GCP PubSub Subscription is
+	// deleted when this PullSubscription is deleted. Defaults to Delete. The finalizer honors this
+	// when tearing down the resource.
+	// +optional
+	SubscriptionReclaimPolicy SubscriptionReclaimPolicy `json:"subscriptionReclaimPolicy,omitempty"`
+}
+
+const (
+	// PullSubscriptionConditionReady has status True when the PullSubscription is ready to send events.
+	PullSubscriptionConditionReady = duckv1alpha1.ConditionReady
+
+	// PullSubscriptionConditionSinkProvided has status True when the PullSubscription has been configured with a sink target.
+	PullSubscriptionConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// PullSubscriptionConditionTransformerProvided has status True when the PullSubscription has been configured with a transformer target.
+	PullSubscriptionConditionTransformerProvided duckv1alpha1.ConditionType = "TransformerProvided"
+
+	// PullSubscriptionConditionDeployed has status True when the PullSubscription has had its receive adapter deployment created.
+	PullSubscriptionConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+
+	// PullSubscriptionConditionSubscribed has status True when the GCP PubSub Subscription has been created.
+	PullSubscriptionConditionSubscribed duckv1alpha1.ConditionType = "SubscriptionReady"
+
+	// PullSubscriptionConditionDeadLetterReady has status True when a DeadLetterTopic has been
+	// configured in the SubscriptionPolicy and applied to the underlying GCP PubSub Subscription.
+	// It is left unset when no DeadLetterTopic is configured.
+	PullSubscriptionConditionDeadLetterReady duckv1alpha1.ConditionType = "DeadLetterReady"
+)
+
+var pullSubscriptionCondSet = duckv1alpha1.NewLivingConditionSet(
+	PullSubscriptionConditionSinkProvided,
+	PullSubscriptionConditionDeployed,
+	PullSubscriptionConditionSubscribed)
+
+// PullSubscriptionStatus defines the observed state of the PullSubscription.
+type PullSubscriptionStatus struct {
+	// inherits duck/v1alpha1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the PullSubscription that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1alpha1.Status `json:",inline"`
+
+	// SinkURI is the current active sink URI that has been configured for the PullSubscription.
+	// +optional
+	SinkURI string `json:"sinkUri,omitempty"`
+
+	// TransformerURI is the current active transformer URI that has been configured for the PullSubscription.
+	// +optional
+	TransformerURI string `json:"transformerUri,omitempty"`
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *PullSubscriptionStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return pullSubscriptionCondSet.Manage(s).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *PullSubscriptionStatus) IsReady() bool {
+	return pullSubscriptionCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *PullSubscriptionStatus) InitializeConditions() {
+	pullSubscriptionCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSink sets the condition that the PullSubscription has a sink configured.
+func (s *PullSubscriptionStatus) MarkSink(uri string) {
+	s.SinkURI = uri
+	if len(uri) > 0 {
+		pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionSinkProvided)
+	} else {
+		pullSubscriptionCondSet.Manage(s).MarkUnknown(PullSubscriptionConditionSinkProvided, "SinkEmpty", "Sink has resolved to empty.")
+	}
+}
+
+// MarkTransformer sets the condition that the PullSubscription has a transformer configured.
+func (s *PullSubscriptionStatus) MarkTransformer(uri string) {
+	s.TransformerURI = uri
+	if len(uri) > 0 {
+		pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionTransformerProvided)
+	} else {
+		pullSubscriptionCondSet.Manage(s).MarkUnknown(PullSubscriptionConditionTransformerProvided, "TransformerEmpty", "Transformer has resolved to empty.")
+	}
+}
+
+// MarkNoSink sets the condition that the PullSubscription does not have a sink configured.
+func (s *PullSubscriptionStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkNoTransformer sets the condition that the PullSubscription does not have a transformer configured.
+func (s *PullSubscriptionStatus) MarkNoTransformer(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionTransformerProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets the condition that the PullSubscription has been deployed.
+func (s *PullSubscriptionStatus) MarkDeployed() {
+	pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionDeployed)
+}
+
+// MarkDeploying sets the condition that the PullSubscription is deploying.
+func (s *PullSubscriptionStatus) MarkDeploying(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkUnknown(PullSubscriptionConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// MarkNotDeployed sets the condition that the PullSubscription has not been deployed.
+func (s *PullSubscriptionStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// MarkSubscribed sets the condition that the GCP PubSub Subscription has been created.
+func (s *PullSubscriptionStatus) MarkSubscribed() {
+	pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionSubscribed)
+}
+
+// MarkNotSubscribed sets the condition that the GCP PubSub Subscription has not been created.
+func (s *PullSubscriptionStatus) MarkNotSubscribed(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionSubscribed, reason, messageFormat, messageA...)
+}
+
+// MarkDeadLetterTopicReady sets the condition that the configured DeadLetterTopic has been applied
+// to the underlying GCP PubSub Subscription.
+func (s *PullSubscriptionStatus) MarkDeadLetterTopicReady() {
+	pullSubscriptionCondSet.Manage(s).MarkTrue(PullSubscriptionConditionDeadLetterReady)
+}
+
+// MarkNoDeadLetterTopic sets the condition that the configured DeadLetterTopic could not be
+// applied to the underlying GCP PubSub Subscription.
+func (s *PullSubscriptionStatus) MarkNoDeadLetterTopic(reason, messageFormat string, messageA ...interface{}) {
+	pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionDeadLetterReady, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PullSubscriptionList contains a list of PullSubscriptions.
+type PullSubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PullSubscription `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PullSubscription{}, &PullSubscriptionList{})
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate implements apis.Validatable.
+func (p *PullSubscription) Validate(ctx context.Context) *apis.FieldError {
+	return p.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate checks that spec's SubscriptionPolicy is within the bounds GCP PubSub itself enforces.
+func (spec *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldError {
+	return spec.SubscriptionPolicy.Validate(ctx).ViaField("subscriptionPolicy")
+}
+
+// CheckImmutableFields implements apis.Immutable. PullSubscription has no immutable fields today.
+func (p *PullSubscription) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	return nil
+}
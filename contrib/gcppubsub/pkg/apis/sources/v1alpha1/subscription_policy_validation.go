@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+// GCP PubSub's own limits on Subscription delivery settings
+// ( see https://cloud.google.com/pubsub/docs/admin#ack_deadline and
+// https://cloud.google.com/pubsub/docs/admin#retention and
+// https://cloud.google.com/pubsub/docs/admin#dead_letter ). Validating these here lets the
+// webhook reject bad values instead of them surfacing as opaque GCP API errors at reconcile time.
+const (
+	minAckDeadlineSeconds = 10
+	maxAckDeadlineSeconds = 600
+
+	minMessageRetentionDuration = 10 * time.Minute
+	maxMessageRetentionDuration = 7 * 24 * time.Hour
+
+	minMaxDeliveryAttempts = 5
+	maxMaxDeliveryAttempts = 100
+
+	minBackoff = 0
+	maxBackoff = 600 * time.Second
+)
+
+// Validate checks that p's fields are within the ranges GCP PubSub itself enforces.
+func (p *SubscriptionPolicy) Validate(ctx context.Context) *apis.FieldError {
+	if p == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+
+	if d := p.AckDeadlineSeconds; d != nil && (*d < minAckDeadlineSeconds || *d > maxAckDeadlineSeconds) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*d, minAckDeadlineSeconds, maxAckDeadlineSeconds, "ackDeadlineSeconds"))
+	}
+
+	if d := p.MessageRetentionDuration; d != nil && (d.Duration < minMessageRetentionDuration || d.Duration > maxMessageRetentionDuration) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(d.Duration, minMessageRetentionDuration, maxMessageRetentionDuration, "messageRetentionDuration"))
+	}
+
+	if n := p.MaxDeliveryAttempts; n != nil && (*n < minMaxDeliveryAttempts || *n > maxMaxDeliveryAttempts) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*n, minMaxDeliveryAttempts, maxMaxDeliveryAttempts, "maxDeliveryAttempts"))
+	}
+
+	if rp := p.RetryPolicy; rp != nil {
+		errs = errs.Also(rp.Validate(ctx).ViaField("retryPolicy"))
+	}
+
+	if p.DeadLetterTopic != nil && p.DeadLetterTopic.Topic == "" {
+		errs = errs.Also(apis.ErrMissingField("topic").ViaField("deadLetterTopic"))
+	}
+
+	return errs
+}
+
+// Validate checks that rp's backoffs are within GCP PubSub's allowed range and that Minimum does
+// not exceed Maximum.
+func (rp *RetryPolicy) Validate(ctx context.Context) *apis.FieldError {
+	if rp == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+
+	if d := rp.MinimumBackoff; d != nil && (d.Duration < minBackoff || d.Duration > maxBackoff) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(d.Duration, minBackoff, maxBackoff, "minimumBackoff"))
+	}
+	if d := rp.MaximumBackoff; d != nil && (d.Duration < minBackoff || d.Duration > maxBackoff) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(d.Duration, minBackoff, maxBackoff, "maximumBackoff"))
+	}
+	if rp.MinimumBackoff != nil && rp.MaximumBackoff != nil && rp.MinimumBackoff.Duration > rp.MaximumBackoff.Duration {
+		errs = errs.Also(&apis.FieldError{
+			Message: "minimumBackoff must not be greater than maximumBackoff",
+			Paths:   []string{"minimumBackoff", "maximumBackoff"},
+		})
+	}
+
+	return errs
+}
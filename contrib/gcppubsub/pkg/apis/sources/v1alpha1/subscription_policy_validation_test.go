@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSubscriptionPolicyValidate(t *testing.T) {
+	int64ptr := func(v int64) *int64 { return &v }
+	int32ptr := func(v int32) *int32 { return &v }
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	tests := []struct {
+		name    string
+		policy  *SubscriptionPolicy
+		wantErr bool
+	}{{
+		name:   "nil policy is valid",
+		policy: nil,
+	}, {
+		name:   "empty policy is valid",
+		policy: &SubscriptionPolicy{},
+	}, {
+		name: "ack deadline within bounds",
+		policy: &SubscriptionPolicy{
+			AckDeadlineSeconds: int64ptr(30),
+		},
+	}, {
+		name: "ack deadline too low",
+		policy: &SubscriptionPolicy{
+			AckDeadlineSeconds: int64ptr(minAckDeadlineSeconds - 1),
+		},
+		wantErr: true,
+	}, {
+		name: "ack deadline too high",
+		policy: &SubscriptionPolicy{
+			AckDeadlineSeconds: int64ptr(maxAckDeadlineSeconds + 1),
+		},
+		wantErr: true,
+	}, {
+		name: "message retention duration too short",
+		policy: &SubscriptionPolicy{
+			MessageRetentionDuration: duration(minMessageRetentionDuration - time.Second),
+		},
+		wantErr: true,
+	}, {
+		name: "message retention duration too long",
+		policy: &SubscriptionPolicy{
+			MessageRetentionDuration: duration(maxMessageRetentionDuration + time.Second),
+		},
+		wantErr: true,
+	}, {
+		name: "max delivery attempts too low",
+		policy: &SubscriptionPolicy{
+			MaxDeliveryAttempts: int32ptr(minMaxDeliveryAttempts - 1),
+		},
+		wantErr: true,
+	}, {
+		name: "max delivery attempts too high",
+		policy: &SubscriptionPolicy{
+			MaxDeliveryAttempts: int32ptr(maxMaxDeliveryAttempts + 1),
+		},
+		wantErr: true,
+	}, {
+		name: "retry policy minimum exceeds maximum",
+		policy: &SubscriptionPolicy{
+			RetryPolicy: &RetryPolicy{
+				MinimumBackoff: duration(10 * time.Second),
+				MaximumBackoff: duration(5 * time.Second),
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "retry policy within bounds",
+		policy: &SubscriptionPolicy{
+			RetryPolicy: &RetryPolicy{
+				MinimumBackoff: duration(5 * time.Second),
+				MaximumBackoff: duration(10 * time.Second),
+			},
+		},
+	}, {
+		name: "dead letter topic missing topic name",
+		policy: &SubscriptionPolicy{
+			DeadLetterTopic: &PubSubTopicReference{Project: "my-project"},
+		},
+		wantErr: true,
+	}, {
+		name: "dead letter topic valid",
+		policy: &SubscriptionPolicy{
+			DeadLetterTopic: &PubSubTopicReference{Topic: "dead-letters"},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.policy.Validate(context.Background())
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %t", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Topic is the Schema for the topics API. It is an internal resource that is not intended to be
+// used directly by end users, but rather as a building block for higher level sources such as
+// GcpPubSubSource and CloudStorageSource that need a GCP PubSub Topic to publish to.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:categories=all,knative,eventing,sources
+type Topic struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TopicSpec   `json:"spec,omitempty"`
+	Status TopicStatus `json:"status,omitempty"`
+}
+
+// Check that Topic can be validated and can be defaulted.
+var _ runtime.Object = (*Topic)(nil)
+
+// Check that Topic will be checked for immutable fields.
+var _ apis.Immutable = (*Topic)(nil)
+
+// Check that Topic can be validated.
+var _ apis.Validatable = (*Topic)(nil)
+
+// Check that Topic implements the Conditions duck type.
+var _ = duck.VerifyType(&Topic{}, &duckv1alpha1.Conditions{})
+
+// TopicSpec defines the desired state of the Topic.
+type TopicSpec struct {
+	// GcpCredsSecret is the credential to use to create/verify the GCP PubSub Topic and publish
+	// to it. The value of the secret entry must be a service account key in the JSON format
+	// ( see https://cloud.google.com/iam/docs/creating-managing-service-account-keys ).
+	GcpCredsSecret corev1.SecretKeySelector `json:"gcpCredsSecret,omitempty"`
+
+	// GoogleCloudProject is the ID of the Google Cloud Project that the PubSub Topic exists in, or
+	// should be created in.
+	GoogleCloudProject string `json:"googleCloudProject,omitempty"`
+
+	// Topic is the ID of the GCP PubSub Topic to use. It must be in the form of the unique
+	// identifier within the project, not the entire name. E.g. it must be 'laconia', not
+	// 'projects/my-gcp-project/topics/laconia'.
+	Topic string `json:"topic,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount that will be granted publisher
+	// permissions on the Topic.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+const (
+	// TopicConditionReady has status True when the Topic is ready to be published to.
+	TopicConditionReady = duckv1alpha1.ConditionReady
+
+	// TopicConditionTopicReady has status True when the underlying GCP PubSub Topic has been
+	// created or verified to exist.
+	TopicConditionTopicReady duckv1alpha1.ConditionType = "TopicReady"
+
+	// TopicConditionPublisherReady has status True when the publisher identity has been granted
+	// permission to publish to the GCP PubSub Topic.
+	TopicConditionPublisherReady duckv1alpha1.ConditionType = "PublisherReady"
+)
+
+var topicCondSet = duckv1alpha1.NewLivingConditionSet(
+	TopicConditionTopicReady,
+	TopicConditionPublisherReady)
+
+// TopicStatus defines the observed state of the Topic.
+type TopicStatus struct {
+	// inherits duck/v1alpha1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Topic that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1alpha1.Status `json:",inline"`
+
+	// ProjectID is the resolved GCP project the Topic was created in.
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *TopicStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return topicCondSet.Manage(s).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *TopicStatus) IsReady() bool {
+	return topicCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *TopicStatus) InitializeConditions() {
+	topicCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkTopicReady sets the condition that the underlying GCP PubSub Topic has been created or
+// verified to exist.
+func (s *TopicStatus) MarkTopicReady() {
+	topicCondSet.Manage(s).MarkTrue(TopicConditionTopicReady)
+}
+
+// MarkNoTopic sets the condition that the underlying GCP PubSub Topic does not exist.
+func (s *TopicStatus) MarkNoTopic(reason, messageFormat string, messageA ...interface{}) {
+	topicCondSet.Manage(s).MarkFalse(TopicConditionTopicReady, reason, messageFormat, messageA...)
+}
+
+// MarkPublisherReady sets the condition that the publisher identity has been granted permission
+// to publish to the Topic.
+func (s *TopicStatus) MarkPublisherReady() {
+	topicCondSet.Manage(s).MarkTrue(TopicConditionPublisherReady)
+}
+
+// MarkNoPublisher sets the condition that the publisher identity does not have permission to
+// publish to the Topic.
+func (s *TopicStatus) MarkNoPublisher(reason, messageFormat string, messageA ...interface{}) {
+	topicCondSet.Manage(s).MarkFalse(TopicConditionPublisherReady, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TopicList contains a list of Topics.
+type TopicList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Topic `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Topic{}, &TopicList{})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate implements apis.Validatable.
+func (t *Topic) Validate(ctx context.Context) *apis.FieldError {
+	return t.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate checks that spec has the fields required to create or verify a GCP PubSub Topic.
+func (spec *TopicSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if spec.GoogleCloudProject == "" {
+		errs = errs.Also(apis.ErrMissingField("googleCloudProject"))
+	}
+	if spec.Topic == "" {
+		errs = errs.Also(apis.ErrMissingField("topic"))
+	}
+	return errs
+}
+
+// CheckImmutableFields implements apis.Immutable. GoogleCloudProject and Topic identify which GCP
+// PubSub Topic this Topic manages; changing either out from under a running reconciler would
+// silently start managing a different Topic instead of actually moving this one.
+func (t *Topic) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	original, ok := og.(*Topic)
+	if !ok {
+		return &apis.FieldError{Message: "The provided original was not a Topic"}
+	}
+
+	var errs *apis.FieldError
+	if t.Spec.GoogleCloudProject != original.Spec.GoogleCloudProject {
+		errs = errs.Also(&apis.FieldError{
+			Message: "GoogleCloudProject is immutable",
+			Paths:   []string{"spec.googleCloudProject"},
+		})
+	}
+	if t.Spec.Topic != original.Spec.Topic {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Topic is immutable",
+			Paths:   []string{"spec.topic"},
+		})
+	}
+	return errs
+}
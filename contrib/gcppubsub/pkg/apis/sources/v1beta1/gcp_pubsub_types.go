@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GcpPubSubSource is the Schema for the gcppubsubsources API.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:categories=all,knative,eventing,sources
+type GcpPubSubSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GcpPubSubSourceSpec   `json:"spec,omitempty"`
+	Status GcpPubSubSourceStatus `json:"status,omitempty"`
+}
+
+// Check that GcpPubSubSource is a runtime.Object.
+var _ runtime.Object = (*GcpPubSubSource)(nil)
+
+// Check that GcpPubSubSource will be checked for immutable fields.
+var _ apis.Immutable = (*GcpPubSubSource)(nil)
+
+// GcpPubSubSource is the storage version of this Schema; it is the conversion hub that all other
+// versions convert to/from. It therefore does not implement apis.Convertible itself.
+
+// GcpPubSubSourceSpec defines the desired state of the GcpPubSubSource.
+type GcpPubSubSourceSpec struct {
+	// SourceSpec inlines the standard Sink and CloudEventOverrides fields shared by all sources.
+	duckv1.SourceSpec `json:",inline"`
+
+	// Secret is the credential used to create the GCP PubSub Topic and to create, update and
+	// delete the GCP PubSub Subscription. The value of the secret entry must be a
+	// service account key in the JSON format
+	// ( see https://cloud.google.com/iam/docs/creating-managing-service-account-keys ).
+	Secret corev1.SecretKeySelector `json:"secret,omitempty"`
+
+	// Project is the ID of the Google Cloud Project that the PubSub Topic exists in.
+	Project string `json:"project,omitempty"`
+
+	// Topic is the ID of the GCP PubSub Topic to Subscribe to. It must be in the form of the
+	// unique identifier within the project, not the entire name. E.g. it must be 'laconia', not
+	// 'projects/my-gcp-project/topics/laconia'.
+	Topic string `json:"topic,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount that will be used to run the Receive
+	// Adapter Deployment.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// SubscriptionPolicy controls the delivery semantics of the underlying GCP PubSub Subscription.
+	// +optional
+	SubscriptionPolicy *SubscriptionPolicy `json:"subscriptionPolicy,omitempty"`
+
+	// SubscriptionReclaimPolicy determines whether the underlying GCP PubSub Subscription is
+	// deleted when this GcpPubSubSource is deleted. Defaults to Delete.
+	// +optional
+	SubscriptionReclaimPolicy SubscriptionReclaimPolicy `json:"subscriptionReclaimPolicy,omitempty"`
+}
+
+const (
+	// GcpPubSubConditionReady has status True when the GcpPubSubSource is ready to send events.
+	GcpPubSubConditionReady = apis.ConditionReady
+
+	// GcpPubSubConditionSinkProvided has status True when the GcpPubSubSource has been configured with a sink target.
+	GcpPubSubConditionSinkProvided apis.ConditionType = "SinkProvided"
+
+	// GcpPubSubConditionDeployed has status True when the GcpPubSubSource has had its receive adapter deployment created.
+	GcpPubSubConditionDeployed apis.ConditionType = "Deployed"
+
+	// GcpPubSubConditionSubscribed has status True when a GCP PubSub Subscription has been created pointing at the created receive adapter deployment.
+	GcpPubSubConditionSubscribed apis.ConditionType = "Subscribed"
+)
+
+var gcpPubSubSourceCondSet = apis.NewLivingConditionSet(
+	GcpPubSubConditionSinkProvided,
+	GcpPubSubConditionDeployed,
+	GcpPubSubConditionSubscribed)
+
+// GcpPubSubSourceStatus defines the observed state of GcpPubSubSource.
+type GcpPubSubSourceStatus struct {
+	// SourceStatus inlines the standard ObservedGeneration, Conditions, SinkURI and
+	// CloudEventAttributes fields shared by all sources.
+	duckv1.SourceStatus `json:",inline"`
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped
+// interface.
+func (*GcpPubSubSource) GetConditionSet() apis.ConditionSet {
+	return gcpPubSubSourceCondSet
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *GcpPubSubSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return gcpPubSubSourceCondSet.Manage(s).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *GcpPubSubSourceStatus) IsReady() bool {
+	return gcpPubSubSourceCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *GcpPubSubSourceStatus) InitializeConditions() {
+	gcpPubSubSourceCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSink sets the condition that the source has a sink configured.
+func (s *GcpPubSubSourceStatus) MarkSink(uri *apis.URL) {
+	s.SinkURI = uri
+	if uri != nil {
+		gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionSinkProvided)
+	} else {
+		gcpPubSubSourceCondSet.Manage(s).MarkUnknown(GcpPubSubConditionSinkProvided, "SinkEmpty", "Sink has resolved to empty.")
+	}
+}
+
+// MarkNoSink sets the condition that the source does not have a sink configured.
+func (s *GcpPubSubSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets the condition that the source has been deployed.
+func (s *GcpPubSubSourceStatus) MarkDeployed() {
+	gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionDeployed)
+}
+
+// MarkNotDeployed sets the condition that the source has not been deployed.
+func (s *GcpPubSubSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	gcpPubSubSourceCondSet.Manage(s).MarkFalse(GcpPubSubConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// MarkSubscribed sets the condition that the GCP PubSub Subscription has been created.
+func (s *GcpPubSubSourceStatus) MarkSubscribed() {
+	gcpPubSubSourceCondSet.Manage(s).MarkTrue(GcpPubSubConditionSubscribed)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GcpPubSubSourceList contains a list of GcpPubSubSources.
+type GcpPubSubSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GcpPubSubSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GcpPubSubSource{}, &GcpPubSubSourceList{})
+}
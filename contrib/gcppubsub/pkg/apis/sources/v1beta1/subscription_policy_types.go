@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubscriptionPolicy controls the delivery semantics of a GCP PubSub Subscription. It mirrors
+// v1alpha1.SubscriptionPolicy field-for-field so that ConvertTo/ConvertFrom can map between them
+// losslessly.
+type SubscriptionPolicy struct {
+	// AckDeadlineSeconds is the number of seconds the PubSub service waits for an ack before
+	// redelivering the message. If unset, the GCP PubSub default is used.
+	// +optional
+	AckDeadlineSeconds *int64 `json:"ackDeadlineSeconds,omitempty"`
+
+	// RetainAckedMessages determines whether acked messages are retained in the Subscription's
+	// backlog for MessageRetentionDuration, so that they may be replayed by seeking.
+	// +optional
+	RetainAckedMessages bool `json:"retainAckedMessages,omitempty"`
+
+	// MessageRetentionDuration is how long to retain unacked (and, if RetainAckedMessages is true,
+	// acked) messages in the Subscription's backlog. If unset, the GCP PubSub default is used.
+	// +optional
+	MessageRetentionDuration *metav1.Duration `json:"messageRetentionDuration,omitempty"`
+
+	// RetryPolicy controls the backoff applied between redelivery attempts.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// DeadLetterTopic is the PubSub Topic that undeliverable messages are forwarded to once
+	// MaxDeliveryAttempts has been exceeded.
+	// +optional
+	DeadLetterTopic *PubSubTopicReference `json:"deadLetterTopic,omitempty"`
+
+	// MaxDeliveryAttempts is the maximum number of delivery attempts before a message is forwarded
+	// to DeadLetterTopic. Only meaningful when DeadLetterTopic is set.
+	// +optional
+	MaxDeliveryAttempts *int32 `json:"maxDeliveryAttempts,omitempty"`
+}
+
+// RetryPolicy specifies the minimum and maximum backoff between redelivery attempts.
+type RetryPolicy struct {
+	// MinimumBackoff is the minimum delay between consecutive redelivery attempts.
+	// +optional
+	MinimumBackoff *metav1.Duration `json:"minimumBackoff,omitempty"`
+
+	// MaximumBackoff is the maximum delay between consecutive redelivery attempts.
+	// +optional
+	MaximumBackoff *metav1.Duration `json:"maximumBackoff,omitempty"`
+}
+
+// PubSubTopicReference identifies a GCP PubSub Topic by project and topic ID.
+type PubSubTopicReference struct {
+	// Project is the ID of the Google Cloud Project the Topic exists in. If unset, the owning
+	// resource's Project is used.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Topic is the ID of the GCP PubSub Topic, e.g. 'laconia', not 'projects/my-gcp-project/topics/laconia'.
+	Topic string `json:"topic,omitempty"`
+}
+
+// SubscriptionReclaimPolicy describes what should happen to the underlying GCP PubSub
+// Subscription when the Kubernetes resource that owns it is deleted.
+type SubscriptionReclaimPolicy string
+
+const (
+	// SubscriptionReclaimDelete means the underlying GCP PubSub Subscription is deleted when the
+	// owning resource is deleted. This is the default.
+	SubscriptionReclaimDelete SubscriptionReclaimPolicy = "Delete"
+
+	// SubscriptionReclaimRetain means the underlying GCP PubSub Subscription is left in place when
+	// the owning resource is deleted.
+	SubscriptionReclaimRetain SubscriptionReclaimPolicy = "Retain"
+)
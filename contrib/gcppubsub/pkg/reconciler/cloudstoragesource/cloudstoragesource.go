@@ -0,0 +1,247 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudstoragesource reconciles the CloudStorageSource resource: it creates a PubSub
+// Topic, points a GCS Bucket notification at it, then reuses a GcpPubSubSource to deliver the
+// notifications to a sink as CloudEvents.
+package cloudstoragesource
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/cloudstoragesource/gcsops"
+)
+
+// finalizerName is added to a CloudStorageSource so that its GCS Bucket notification, which is
+// not a Kubernetes-owned object, can be deleted before the resource is actually removed.
+const finalizerName = "cloudstoragesources.sources.eventing.knative.dev"
+
+// CreateGCSClientFn builds a gcsops.Interface authenticated for s's GcpCredsSecret.
+type CreateGCSClientFn func(ctx context.Context, c client.Client, s *sourcesv1alpha1.CloudStorageSource) (gcsops.Interface, error)
+
+// DefaultCreateGCSClient is the production CreateGCSClientFn.
+func DefaultCreateGCSClient(ctx context.Context, c client.Client, s *sourcesv1alpha1.CloudStorageSource) (gcsops.Interface, error) {
+	return gcsops.NewFromSecret(ctx, c, s.Namespace, s.Spec.GcpCredsSecret)
+}
+
+// Reconciler reconciles a CloudStorageSource.
+type Reconciler struct {
+	Client          client.Client
+	CreateGCSClient CreateGCSClientFn
+}
+
+// Reconcile implements controller-runtime's reconcile.Reconciler.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var s sourcesv1alpha1.CloudStorageSource
+	if err := r.Client.Get(ctx, req.NamespacedName, &s); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !s.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &s)
+	}
+
+	if !containsString(s.Finalizers, finalizerName) {
+		s.Finalizers = append(s.Finalizers, finalizerName)
+		if err := r.Client.Update(ctx, &s); err != nil {
+			return ctrl.Result{}, fmt.Errorf("adding finalizer: %w", err)
+		}
+	}
+
+	s.Status.InitializeConditions()
+	reconcileErr := r.reconcile(ctx, &s)
+
+	if err := r.Client.Status().Update(ctx, &s); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating status: %w", err)
+	}
+	return ctrl.Result{}, reconcileErr
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, s *sourcesv1alpha1.CloudStorageSource) error {
+	t, err := r.reconcileTopic(ctx, s)
+	if err != nil {
+		return err
+	}
+	if !t.Status.IsReady() {
+		s.Status.MarkNoPubSubTopic("TopicNotReady", "Topic %s is not yet ready.", t.Name)
+		return nil
+	}
+	s.Status.MarkPubSubTopic(t.Spec.Topic)
+
+	if s.Status.NotificationID == "" {
+		notificationID, err := r.reconcileNotification(ctx, s, t.Spec.Topic)
+		if err != nil {
+			s.Status.MarkNoNotification("NotificationCreationFailed", "%v", err)
+			return err
+		}
+		s.Status.MarkNotification(notificationID)
+	}
+
+	pubsub, err := r.reconcilePubSubSource(ctx, s, t.Spec.Topic)
+	if err != nil {
+		return err
+	}
+	if !pubsub.Status.IsReady() {
+		s.Status.MarkNoPubSubSource("GcpPubSubSourceNotReady", "GcpPubSubSource %s is not yet ready.", pubsub.Name)
+		return nil
+	}
+	s.Status.MarkPubSubSource(pubsub.Status.SinkURI)
+	return nil
+}
+
+// reconcileTopic ensures the backing PubSub Topic that the GCS Bucket notification will publish
+// to exists, reusing the Topic primitive rather than talking to GCP PubSub directly.
+func (r *Reconciler) reconcileTopic(ctx context.Context, s *sourcesv1alpha1.CloudStorageSource) (*sourcesv1alpha1.Topic, error) {
+	want := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.Name + "-topic",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, sourcesv1alpha1.SchemeGroupVersion.WithKind("CloudStorageSource")),
+			},
+		},
+		Spec: sourcesv1alpha1.TopicSpec{
+			GcpCredsSecret:     s.Spec.GcpCredsSecret,
+			GoogleCloudProject: s.Spec.GoogleCloudProject,
+			Topic:              s.Name + "-" + string(s.UID),
+			ServiceAccountName: s.Spec.ServiceAccountName,
+		},
+	}
+
+	var got sourcesv1alpha1.Topic
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(want), &got)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.Client.Create(ctx, want); err != nil {
+			return nil, fmt.Errorf("creating topic: %w", err)
+		}
+		return want, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting topic: %w", err)
+	}
+	return &got, nil
+}
+
+func (r *Reconciler) reconcileNotification(ctx context.Context, s *sourcesv1alpha1.CloudStorageSource, topicID string) (string, error) {
+	ops, err := r.CreateGCSClient(ctx, r.Client, s)
+	if err != nil {
+		return "", fmt.Errorf("creating GCS client: %w", err)
+	}
+
+	eventTypes := make([]string, 0, len(s.Spec.EventTypes))
+	for _, t := range s.Spec.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+
+	return ops.CreateNotification(ctx, s.Spec.Bucket, gcsops.NotificationConfig{
+		TopicProjectID:   s.Spec.GoogleCloudProject,
+		TopicID:          topicID,
+		EventTypes:       eventTypes,
+		ObjectNamePrefix: s.Spec.ObjectNamePrefix,
+		PayloadFormat:    s.Spec.PayloadFormat,
+	})
+}
+
+// reconcilePubSubSource ensures the GcpPubSubSource that actually delivers the notifications
+// published to topicID to s's sink exists.
+func (r *Reconciler) reconcilePubSubSource(ctx context.Context, s *sourcesv1alpha1.CloudStorageSource, topicID string) (*sourcesv1alpha1.GcpPubSubSource, error) {
+	want := &sourcesv1alpha1.GcpPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      s.Name + "-pubsub",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, sourcesv1alpha1.SchemeGroupVersion.WithKind("CloudStorageSource")),
+			},
+		},
+		Spec: sourcesv1alpha1.GcpPubSubSourceSpec{
+			GcpCredsSecret:     s.Spec.GcpCredsSecret,
+			GoogleCloudProject: s.Spec.GoogleCloudProject,
+			Topic:              topicID,
+			Sink:               s.Spec.Sink,
+			Transformer:        s.Spec.Transformer,
+			ServiceAccountName: s.Spec.ServiceAccountName,
+		},
+	}
+
+	var got sourcesv1alpha1.GcpPubSubSource
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(want), &got)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.Client.Create(ctx, want); err != nil {
+			return nil, fmt.Errorf("creating GcpPubSubSource: %w", err)
+		}
+		return want, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting GcpPubSubSource: %w", err)
+	}
+
+	got.Spec = want.Spec
+	if err := r.Client.Update(ctx, &got); err != nil {
+		return nil, fmt.Errorf("updating GcpPubSubSource: %w", err)
+	}
+	return &got, nil
+}
+
+// finalize deletes s's GCS Bucket notification, then removes finalizerName so deletion can
+// proceed. The backing Topic and GcpPubSubSource are Kubernetes-owned and are garbage collected
+// by the API server once s is gone.
+func (r *Reconciler) finalize(ctx context.Context, s *sourcesv1alpha1.CloudStorageSource) error {
+	if !containsString(s.Finalizers, finalizerName) {
+		return nil
+	}
+
+	if s.Status.NotificationID != "" {
+		ops, err := r.CreateGCSClient(ctx, r.Client, s)
+		if err != nil {
+			return fmt.Errorf("creating GCS client to delete notification: %w", err)
+		}
+		if err := ops.DeleteNotification(ctx, s.Spec.Bucket, s.Status.NotificationID); err != nil {
+			return fmt.Errorf("deleting notification %q: %w", s.Status.NotificationID, err)
+		}
+	}
+
+	s.Finalizers = removeString(s.Finalizers, finalizerName)
+	return r.Client.Update(ctx, s)
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(ss []string, s string) []string {
+	out := ss[:0]
+	for _, v := range ss {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
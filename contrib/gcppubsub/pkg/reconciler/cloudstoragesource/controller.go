@@ -0,0 +1,34 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudstoragesource
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+// SetupWithManager registers r with mgr to reconcile CloudStorageSources, watching the Topic and
+// GcpPubSubSource children it owns so their status changes trigger a re-reconcile of the parent
+// without waiting for the next CloudStorageSource change.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sourcesv1alpha1.CloudStorageSource{}).
+		Owns(&sourcesv1alpha1.Topic{}).
+		Owns(&sourcesv1alpha1.GcpPubSubSource{}).
+		Complete(r)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcsops
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// client is the production Interface, backed by a real *storage.Client.
+type client struct {
+	c *storage.Client
+}
+
+// New dials a real GCS client authenticated with credsJSON.
+func New(ctx context.Context, credsJSON []byte) (Interface, error) {
+	c, err := storage.NewClient(ctx, option.WithCredentialsJSON(credsJSON))
+	if err != nil {
+		return nil, fmt.Errorf("creating GCS client: %w", err)
+	}
+	return &client{c: c}, nil
+}
+
+func (cl *client) CreateNotification(ctx context.Context, bucket string, cfg NotificationConfig) (string, error) {
+	n, err := cl.c.Bucket(bucket).AddNotification(ctx, &storage.Notification{
+		TopicProjectID:   cfg.TopicProjectID,
+		TopicID:          cfg.TopicID,
+		EventTypes:       cfg.EventTypes,
+		ObjectNamePrefix: cfg.ObjectNamePrefix,
+		PayloadFormat:    cfg.PayloadFormat,
+	})
+	if err != nil {
+		return "", err
+	}
+	return n.ID, nil
+}
+
+func (cl *client) DeleteNotification(ctx context.Context, bucket, notificationID string) error {
+	err := cl.c.Bucket(bucket).DeleteNotification(ctx, notificationID)
+	if err != nil && err != storage.ErrNotificationNotFound {
+		return err
+	}
+	return nil
+}
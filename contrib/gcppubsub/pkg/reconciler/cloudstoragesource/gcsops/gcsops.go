@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcsops abstracts the subset of the GCS API the CloudStorageSource reconciler needs, so
+// that the reconciler can be tested against a fake instead of talking to GCP.
+package gcsops
+
+import "context"
+
+// Interface is the set of GCS bucket notification operations the reconciler depends on.
+type Interface interface {
+	// CreateNotification creates a Pub/Sub notification on bucket per cfg and returns the GCS
+	// notification ID.
+	CreateNotification(ctx context.Context, bucket string, cfg NotificationConfig) (string, error)
+
+	// DeleteNotification deletes notificationID on bucket. It is not an error if it does not exist.
+	DeleteNotification(ctx context.Context, bucket, notificationID string) error
+}
+
+// NotificationConfig describes the Pub/Sub notification to create on a bucket.
+type NotificationConfig struct {
+	// TopicProjectID is the project the destination PubSub Topic lives in.
+	TopicProjectID string
+
+	// TopicID is the destination PubSub Topic.
+	TopicID string
+
+	// EventTypes restricts which GCS event types are sent. Empty means all.
+	EventTypes []string
+
+	// ObjectNamePrefix restricts notifications to objects whose name has this prefix. Empty means
+	// no restriction.
+	ObjectNamePrefix string
+
+	// PayloadFormat is the notification payload format, e.g. "JSON_API_V1" or "NONE".
+	PayloadFormat string
+}
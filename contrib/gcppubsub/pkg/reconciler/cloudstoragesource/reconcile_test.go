@@ -0,0 +1,151 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudstoragesource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/cloudstoragesource/gcsops"
+)
+
+// fakeOps is a stub gcsops.Interface that tracks notifications in memory, so Reconciler.Reconcile
+// can be exercised without talking to GCS.
+type fakeOps struct {
+	created int
+	deleted []string
+}
+
+func (f *fakeOps) CreateNotification(ctx context.Context, bucket string, cfg gcsops.NotificationConfig) (string, error) {
+	f.created++
+	return "notification-1", nil
+}
+
+func (f *fakeOps) DeleteNotification(ctx context.Context, bucket, notificationID string) error {
+	f.deleted = append(f.deleted, notificationID)
+	return nil
+}
+
+func newTestReconciler(t *testing.T, ops *fakeOps, objs ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := sourcesv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("registering sources/v1alpha1: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &Reconciler{
+		Client: c,
+		CreateGCSClient: func(ctx context.Context, _ client.Client, _ *sourcesv1alpha1.CloudStorageSource) (gcsops.Interface, error) {
+			return ops, nil
+		},
+	}, c
+}
+
+func TestReconcileCreatesTopicAndWaitsForIt(t *testing.T) {
+	s := &sourcesv1alpha1.CloudStorageSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s"},
+		Spec:       sourcesv1alpha1.CloudStorageSourceSpec{Bucket: "my-bucket"},
+	}
+	ops := &fakeOps{}
+	r, c := newTestReconciler(t, ops, s)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(s)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	var topic sourcesv1alpha1.Topic
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "s-topic"}, &topic); err != nil {
+		t.Fatalf("getting child Topic: %v", err)
+	}
+	if ops.created != 0 {
+		t.Errorf("created = %d notifications, want 0 before the backing Topic is ready", ops.created)
+	}
+}
+
+func TestReconcileCreatesNotificationOnceTopicReady(t *testing.T) {
+	s := &sourcesv1alpha1.CloudStorageSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s"},
+		Spec:       sourcesv1alpha1.CloudStorageSourceSpec{Bucket: "my-bucket"},
+	}
+	topic := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s-topic"},
+		Spec:       sourcesv1alpha1.TopicSpec{Topic: "s-topic-id"},
+	}
+	topic.Status.InitializeConditions()
+	topic.Status.MarkTopicReady()
+	topic.Status.MarkPublisherReady()
+
+	ops := &fakeOps{}
+	r, c := newTestReconciler(t, ops, s, topic)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(s)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if ops.created != 1 {
+		t.Errorf("created = %d notifications, want 1", ops.created)
+	}
+
+	var got sourcesv1alpha1.CloudStorageSource
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(s), &got); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if got.Status.NotificationID != "notification-1" {
+		t.Errorf("Status.NotificationID = %q, want %q", got.Status.NotificationID, "notification-1")
+	}
+
+	var pubsub sourcesv1alpha1.GcpPubSubSource
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "s-pubsub"}, &pubsub); err != nil {
+		t.Fatalf("getting child GcpPubSubSource: %v", err)
+	}
+	if pubsub.Spec.Topic != "s-topic-id" {
+		t.Errorf("child GcpPubSubSource.Spec.Topic = %q, want %q", pubsub.Spec.Topic, "s-topic-id")
+	}
+}
+
+func TestFinalizeDeletesNotification(t *testing.T) {
+	now := metav1.NewTime(time.Unix(0, 0))
+	s := &sourcesv1alpha1.CloudStorageSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              "s",
+			Finalizers:        []string{finalizerName},
+			DeletionTimestamp: &now,
+		},
+		Spec: sourcesv1alpha1.CloudStorageSourceSpec{Bucket: "my-bucket"},
+	}
+	s.Status.NotificationID = "notification-1"
+
+	ops := &fakeOps{}
+	r, _ := newTestReconciler(t, ops, s)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(s)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if len(ops.deleted) != 1 || ops.deleted[0] != "notification-1" {
+		t.Errorf("deleted = %v, want a single delete of %q", ops.deleted, "notification-1")
+	}
+}
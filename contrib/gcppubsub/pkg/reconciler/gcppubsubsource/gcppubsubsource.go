@@ -0,0 +1,185 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcppubsubsource reconciles the GcpPubSubSource resource. GcpPubSubSource is a thin
+// aggregator: it creates a Topic and a PullSubscription to do the actual work, then rolls their
+// conditions up into its own.
+package gcppubsubsource
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"knative.dev/eventing-contrib/gcppubsub/pkg/adapter/converters"
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+// Reconciler reconciles a GcpPubSubSource by creating/updating the Topic and PullSubscription it
+// is backed by.
+type Reconciler struct {
+	Client client.Client
+}
+
+// Reconcile implements controller-runtime's reconcile.Reconciler.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var s sourcesv1alpha1.GcpPubSubSource
+	if err := r.Client.Get(ctx, req.NamespacedName, &s); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	s.Status.InitializeConditions()
+	reconcileErr := r.reconcile(ctx, &s)
+
+	if err := r.Client.Status().Update(ctx, &s); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating status: %w", err)
+	}
+	return ctrl.Result{}, reconcileErr
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, s *sourcesv1alpha1.GcpPubSubSource) error {
+	t, err := r.reconcileTopic(ctx, s)
+	if err != nil {
+		return err
+	}
+	s.Status.TopicName = t.Name
+	s.Status.PropagateTopicStatus(&t.Status)
+	if !t.Status.IsReady() {
+		// The PullSubscription can't do anything useful until the Topic it points at exists, so
+		// don't create it yet; PropagateTopicStatus has already reflected why.
+		return nil
+	}
+
+	ps, err := r.reconcilePullSubscription(ctx, s)
+	if err != nil {
+		return err
+	}
+	s.Status.PullSubscriptionName = ps.Name
+	s.Status.PropagatePullSubscriptionStatus(&ps.Status)
+
+	attributesFor, err := converters.GetAttributes(converterName(s))
+	if err != nil {
+		return fmt.Errorf("looking up attributes for converter %q: %w", converterName(s), err)
+	}
+	s.Status.MarkCloudEventAttributes(attributesFor(converters.Context{
+		Project:      s.Spec.GoogleCloudProject,
+		Topic:        s.Spec.Topic,
+		Subscription: ps.Spec.Subscription,
+	}))
+	return nil
+}
+
+// converterName returns s's configured Converter, defaulting to raw_pubsub like the receive
+// adapter itself does when Spec.Converter is unset.
+func converterName(s *sourcesv1alpha1.GcpPubSubSource) string {
+	if s.Spec.Converter != "" {
+		return s.Spec.Converter
+	}
+	return converters.RawPubSub
+}
+
+func (r *Reconciler) reconcileTopic(ctx context.Context, s *sourcesv1alpha1.GcpPubSubSource) (*sourcesv1alpha1.Topic, error) {
+	want := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      childName(s, "topic"),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, sourcesv1alpha1.SchemeGroupVersion.WithKind("GcpPubSubSource")),
+			},
+		},
+		Spec: sourcesv1alpha1.TopicSpec{
+			GcpCredsSecret:     s.Spec.GcpCredsSecret,
+			GoogleCloudProject: s.Spec.GoogleCloudProject,
+			Topic:              s.Spec.Topic,
+			ServiceAccountName: s.Spec.ServiceAccountName,
+		},
+	}
+
+	var got sourcesv1alpha1.Topic
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(want), &got)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.Client.Create(ctx, want); err != nil {
+			return nil, fmt.Errorf("creating topic: %w", err)
+		}
+		return want, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting topic: %w", err)
+	}
+
+	got.Spec = want.Spec
+	if err := r.Client.Update(ctx, &got); err != nil {
+		return nil, fmt.Errorf("updating topic: %w", err)
+	}
+	return &got, nil
+}
+
+func (r *Reconciler) reconcilePullSubscription(ctx context.Context, s *sourcesv1alpha1.GcpPubSubSource) (*sourcesv1alpha1.PullSubscription, error) {
+	want := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      childName(s, "pullsubscription"),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, sourcesv1alpha1.SchemeGroupVersion.WithKind("GcpPubSubSource")),
+			},
+		},
+		Spec: sourcesv1alpha1.PullSubscriptionSpec{
+			GcpCredsSecret:            s.Spec.GcpCredsSecret,
+			GoogleCloudProject:        s.Spec.GoogleCloudProject,
+			Topic:                     s.Spec.Topic,
+			Sink:                      s.Spec.Sink,
+			Transformer:               s.Spec.Transformer,
+			ServiceAccountName:        s.Spec.ServiceAccountName,
+			Converter:                 s.Spec.Converter,
+			SubscriptionPolicy:        s.Spec.SubscriptionPolicy,
+			SubscriptionReclaimPolicy: s.Spec.SubscriptionReclaimPolicy,
+		},
+	}
+
+	var got sourcesv1alpha1.PullSubscription
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(want), &got)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.Client.Create(ctx, want); err != nil {
+			return nil, fmt.Errorf("creating pullsubscription: %w", err)
+		}
+		return want, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting pullsubscription: %w", err)
+	}
+
+	// Subscription is derived and persisted by the PullSubscription reconciler itself; want.Spec
+	// never sets it, so copy it across rather than overwriting it back to empty.
+	want.Spec.Subscription = got.Spec.Subscription
+	got.Spec = want.Spec
+	if err := r.Client.Update(ctx, &got); err != nil {
+		return nil, fmt.Errorf("updating pullsubscription: %w", err)
+	}
+	return &got, nil
+}
+
+// childName derives a deterministic name for the Topic/PullSubscription owned by s, so that
+// reconciling s repeatedly finds the same child instead of creating duplicates.
+func childName(s *sourcesv1alpha1.GcpPubSubSource, suffix string) string {
+	return s.Name + "-" + suffix
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcppubsubsource
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+func newTestReconciler(t *testing.T, objs ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := sourcesv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("registering sources/v1alpha1: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &Reconciler{Client: c}, c
+}
+
+func TestReconcileCreatesTopicAndWaitsForIt(t *testing.T) {
+	s := &sourcesv1alpha1.GcpPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s"},
+		Spec:       sourcesv1alpha1.GcpPubSubSourceSpec{Topic: "my-topic"},
+	}
+	r, c := newTestReconciler(t, s)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(s)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	var topic sourcesv1alpha1.Topic
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: childName(s, "topic")}, &topic); err != nil {
+		t.Fatalf("getting child Topic: %v", err)
+	}
+	if topic.Spec.Topic != "my-topic" {
+		t.Errorf("child Topic.Spec.Topic = %q, want %q", topic.Spec.Topic, "my-topic")
+	}
+
+	var ps sourcesv1alpha1.PullSubscription
+	err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: childName(s, "pullsubscription")}, &ps)
+	if !apierrs.IsNotFound(err) {
+		t.Errorf("child PullSubscription should not be created before its Topic is ready; got err = %v", err)
+	}
+}
+
+func TestReconcilePreservesChildSubscriptionAcrossUpdate(t *testing.T) {
+	s := &sourcesv1alpha1.GcpPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s"},
+		Spec:       sourcesv1alpha1.GcpPubSubSourceSpec{Topic: "my-topic"},
+	}
+	topic := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: childName(s, "topic")},
+	}
+	topic.Status.InitializeConditions()
+	topic.Status.MarkTopicReady()
+	topic.Status.MarkPublisherReady()
+
+	ps := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: childName(s, "pullsubscription")},
+		Spec:       sourcesv1alpha1.PullSubscriptionSpec{Topic: "my-topic", Subscription: "already-derived"},
+	}
+
+	r, c := newTestReconciler(t, s, topic, ps)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(s)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	var got sourcesv1alpha1.PullSubscription
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(ps), &got); err != nil {
+		t.Fatalf("getting child PullSubscription: %v", err)
+	}
+	if got.Spec.Subscription != "already-derived" {
+		t.Errorf("Spec.Subscription = %q, want %q (must not be stomped by the aggregator's update)", got.Spec.Subscription, "already-derived")
+	}
+}
+
+func TestReconcilePropagatesDeadLetterReady(t *testing.T) {
+	s := &sourcesv1alpha1.GcpPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s"},
+		Spec:       sourcesv1alpha1.GcpPubSubSourceSpec{Topic: "my-topic"},
+	}
+	topic := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: childName(s, "topic")},
+	}
+	topic.Status.InitializeConditions()
+	topic.Status.MarkTopicReady()
+	topic.Status.MarkPublisherReady()
+
+	ps := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: childName(s, "pullsubscription")},
+		Spec:       sourcesv1alpha1.PullSubscriptionSpec{Topic: "my-topic"},
+	}
+	ps.Status.InitializeConditions()
+	ps.Status.MarkDeadLetterTopicReady()
+
+	r, c := newTestReconciler(t, s, topic, ps)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(s)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	var got sourcesv1alpha1.GcpPubSubSource
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(s), &got); err != nil {
+		t.Fatalf("getting GcpPubSubSource: %v", err)
+	}
+	cond := got.Status.GetCondition(sourcesv1alpha1.GcpPubSubConditionDeadLetterReady)
+	if cond == nil || cond.Status != corev1.ConditionTrue {
+		t.Errorf("DeadLetterReady condition = %+v, want True", cond)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsubops
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/iam"
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// client is the production Interface, backed by a real *pubsub.Client.
+type client struct {
+	c *pubsub.Client
+}
+
+// New dials a real GCP PubSub client authenticated with credsJSON, scoped to project.
+func New(ctx context.Context, project string, credsJSON []byte) (Interface, error) {
+	c, err := pubsub.NewClient(ctx, project, option.WithCredentialsJSON(credsJSON))
+	if err != nil {
+		return nil, fmt.Errorf("creating PubSub client: %w", err)
+	}
+	return &client{c: c}, nil
+}
+
+func (cl *client) TopicExists(ctx context.Context, topicID string) (bool, error) {
+	return cl.c.Topic(topicID).Exists(ctx)
+}
+
+func (cl *client) CreateTopic(ctx context.Context, topicID string) error {
+	_, err := cl.c.CreateTopic(ctx, topicID)
+	return err
+}
+
+func (cl *client) AddTopicIAMPolicyBinding(ctx context.Context, topicID, role, member string) error {
+	h := cl.c.Topic(topicID).IAM()
+	policy, err := h.Policy(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching IAM policy for topic %q: %w", topicID, err)
+	}
+	policy.Add(member, iam.RoleName(role))
+	if err := h.SetPolicy(ctx, policy); err != nil {
+		return fmt.Errorf("setting IAM policy for topic %q: %w", topicID, err)
+	}
+	return nil
+}
+
+func (cl *client) SubscriptionExists(ctx context.Context, subscriptionID string) (bool, error) {
+	return cl.c.Subscription(subscriptionID).Exists(ctx)
+}
+
+func (cl *client) CreateSubscription(ctx context.Context, subscriptionID, topicID string, cfg SubscriptionConfig) error {
+	_, err := cl.c.CreateSubscription(ctx, subscriptionID, subscriptionConfig(cl.c.Topic(topicID), cfg))
+	return err
+}
+
+func (cl *client) UpdateSubscription(ctx context.Context, subscriptionID string, cfg SubscriptionConfig) error {
+	sub := cl.c.Subscription(subscriptionID)
+	want := subscriptionConfig(nil, cfg)
+	_, err := sub.Update(ctx, pubsub.SubscriptionConfigToUpdate{
+		AckDeadline:         want.AckDeadline,
+		RetainAckedMessages: want.RetainAckedMessages,
+		RetentionDuration:   want.RetentionDuration,
+		RetryPolicy:         want.RetryPolicy,
+		DeadLetterPolicy:    want.DeadLetterPolicy,
+	})
+	return err
+}
+
+func (cl *client) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	err := cl.c.Subscription(subscriptionID).Delete(ctx)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// subscriptionConfig translates our SubscriptionConfig into the pubsub package's. On a Create, a
+// zero-valued field leaves the setting at the GCP PubSub default. On an Update, a zero
+// AckDeadline or MessageRetentionDuration leaves the Subscription's current value unchanged; it
+// is not reset to the default. topic is nil when the result is only used for its delivery
+// settings (Update), which don't carry a Topic.
+//
+// For an Update, pubsub.SubscriptionConfigToUpdate treats a nil RetryPolicy/DeadLetterPolicy as
+// "leave whatever is already on the Subscription unchanged", not "clear it" - so when topic is nil
+// we always populate an explicit, possibly zero-valued, policy, so that removing a
+// SubscriptionPolicy field actually clears the corresponding setting instead of leaving the old
+// value in place. A Create has no existing state to preserve, so it only sets a policy when one is
+// actually configured.
+func subscriptionConfig(topic *pubsub.Topic, cfg SubscriptionConfig) pubsub.SubscriptionConfig {
+	sc := pubsub.SubscriptionConfig{
+		Topic:               topic,
+		AckDeadline:         cfg.AckDeadline,
+		RetainAckedMessages: cfg.RetainAckedMessages,
+		RetentionDuration:   cfg.MessageRetentionDuration,
+	}
+	if cfg.MinimumBackoff > 0 || cfg.MaximumBackoff > 0 || topic == nil {
+		sc.RetryPolicy = &pubsub.RetryPolicy{
+			MinimumBackoff: cfg.MinimumBackoff,
+			MaximumBackoff: cfg.MaximumBackoff,
+		}
+	}
+	if cfg.DeadLetterTopic != "" || topic == nil {
+		sc.DeadLetterPolicy = &pubsub.DeadLetterPolicy{
+			DeadLetterTopic:     cfg.DeadLetterTopic,
+			MaxDeliveryAttempts: int(cfg.MaxDeliveryAttempts),
+		}
+	}
+	return sc
+}
+
+// isNotFound reports whether err is a gRPC NotFound error, which DeleteSubscription treats as
+// success since the desired end state (no Subscription) is already reached.
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
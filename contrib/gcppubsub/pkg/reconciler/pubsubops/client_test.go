@@ -0,0 +1,51 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsubops
+
+import (
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+)
+
+func TestSubscriptionConfigForCreateOmitsUnsetPolicies(t *testing.T) {
+	// A non-nil topic selects the Create path.
+	sc := subscriptionConfig(&pubsub.Topic{}, SubscriptionConfig{})
+	sc.Topic = nil // only the delivery settings are under test
+
+	if sc.RetryPolicy != nil {
+		t.Errorf("RetryPolicy = %+v, want nil (Create with no RetryPolicy configured)", sc.RetryPolicy)
+	}
+	if sc.DeadLetterPolicy != nil {
+		t.Errorf("DeadLetterPolicy = %+v, want nil (Create with no DeadLetterTopic configured)", sc.DeadLetterPolicy)
+	}
+}
+
+// TestSubscriptionConfigForUpdateAlwaysSetsPolicies exercises the fix for UpdateSubscription
+// silently leaving a previously-set RetryPolicy/DeadLetterPolicy in place when the corresponding
+// SubscriptionPolicy field is removed: pubsub.SubscriptionConfigToUpdate treats a nil policy as
+// "leave unchanged", not "clear", so an Update must always send an explicit policy.
+func TestSubscriptionConfigForUpdateAlwaysSetsPolicies(t *testing.T) {
+	sc := subscriptionConfig(nil, SubscriptionConfig{})
+
+	if sc.RetryPolicy == nil {
+		t.Error("RetryPolicy = nil, want non-nil so Update clears a previously-set RetryPolicy")
+	}
+	if sc.DeadLetterPolicy == nil {
+		t.Error("DeadLetterPolicy = nil, want non-nil so Update clears a previously-set DeadLetterPolicy")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pubsubops abstracts the subset of the GCP PubSub API that the Topic and
+// PullSubscription reconcilers need, so that the reconcilers can be tested against a fake
+// instead of talking to GCP.
+package pubsubops
+
+import (
+	"context"
+	"time"
+)
+
+// Interface is the set of GCP PubSub operations the reconcilers depend on.
+type Interface interface {
+	// TopicExists reports whether topicID already exists in the project the Interface was created
+	// for.
+	TopicExists(ctx context.Context, topicID string) (bool, error)
+
+	// CreateTopic creates topicID in the project the Interface was created for.
+	CreateTopic(ctx context.Context, topicID string) error
+
+	// AddTopicIAMPolicyBinding grants member the given role (e.g. "roles/pubsub.publisher") on
+	// topicID.
+	AddTopicIAMPolicyBinding(ctx context.Context, topicID, role, member string) error
+
+	// SubscriptionExists reports whether subscriptionID already exists.
+	SubscriptionExists(ctx context.Context, subscriptionID string) (bool, error)
+
+	// CreateSubscription creates subscriptionID against topicID with the given delivery settings.
+	CreateSubscription(ctx context.Context, subscriptionID, topicID string, cfg SubscriptionConfig) error
+
+	// UpdateSubscription reconciles subscriptionID's delivery settings to match cfg.
+	UpdateSubscription(ctx context.Context, subscriptionID string, cfg SubscriptionConfig) error
+
+	// DeleteSubscription deletes subscriptionID. It is not an error if it does not exist.
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+}
+
+// SubscriptionConfig captures the GCP PubSub Subscription delivery settings the PullSubscription
+// reconciler derives from SubscriptionPolicy. On CreateSubscription a zero-valued field means
+// "use the GCP PubSub default", mirroring SubscriptionPolicy's own optional fields. On
+// UpdateSubscription a zero AckDeadline or MessageRetentionDuration leaves the current value
+// unchanged.
+type SubscriptionConfig struct {
+	// AckDeadline is the Subscription's acknowledgement deadline.
+	AckDeadline time.Duration
+
+	// RetainAckedMessages mirrors SubscriptionPolicy.RetainAckedMessages.
+	RetainAckedMessages bool
+
+	// MessageRetentionDuration mirrors SubscriptionPolicy.MessageRetentionDuration.
+	MessageRetentionDuration time.Duration
+
+	// MinimumBackoff and MaximumBackoff mirror SubscriptionPolicy.RetryPolicy.
+	MinimumBackoff time.Duration
+	MaximumBackoff time.Duration
+
+	// DeadLetterTopic is the fully qualified name of the dead-letter Topic
+	// (projects/{project}/topics/{topic}), or empty if none is configured.
+	DeadLetterTopic string
+
+	// MaxDeliveryAttempts mirrors SubscriptionPolicy.MaxDeliveryAttempts. Only meaningful when
+	// DeadLetterTopic is set.
+	MaxDeliveryAttempts int32
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsubops
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewFromSecret builds an Interface authenticated with the service account key stored at
+// secretRef in namespace, scoped to project. It is the credential path every reconciler uses:
+// GcpCredsSecret on Topic/PullSubscription never contains the key inline, only a reference to it.
+func NewFromSecret(ctx context.Context, c client.Client, namespace string, secretRef corev1.SecretKeySelector, project string) (Interface, error) {
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: namespace, Name: secretRef.Name}
+	if err := c.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("getting secret %s: %w", key, err)
+	}
+
+	credsJSON, ok := secret.Data[secretRef.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no key %q", key, secretRef.Key)
+	}
+
+	return New(ctx, project, credsJSON)
+}
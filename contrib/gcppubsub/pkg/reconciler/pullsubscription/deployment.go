@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/eventing-contrib/gcppubsub/pkg/adapter/converters"
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+// receiveAdapterImage is the container image of the shared CloudEvents receive adapter binary
+// (see gcppubsub/pkg/adapter). It is a var, rather than a const, so it can be overridden by
+// ldflags at build time to pin a specific release.
+var receiveAdapterImage = "gcr.io/knative-releases/knative.dev/eventing-contrib/gcppubsub/cmd/receive_adapter"
+
+// deploymentName returns the name of the receive adapter Deployment owned by p.
+func deploymentName(p *sourcesv1alpha1.PullSubscription) string {
+	return p.Name + "-receive-adapter"
+}
+
+// makeDeployment builds the receive adapter Deployment for p. sinkURI and transformerURI are the
+// resolved destinations p.Spec.Sink/Transformer point at.
+func makeDeployment(p *sourcesv1alpha1.PullSubscription, sinkURI, transformerURI string) *appsv1.Deployment {
+	labels := map[string]string{
+		"receive-adapter":                          "gcppubsub",
+		"events.cloud.google.com/pullsubscription": p.Name,
+	}
+
+	converter := p.Spec.Converter
+	if converter == "" {
+		converter = converters.RawPubSub
+	}
+
+	env := []corev1.EnvVar{
+		{Name: envProjectID, Value: p.Spec.GoogleCloudProject},
+		{Name: envTopicID, Value: p.Spec.Topic},
+		{Name: envSubscriptionID, Value: p.Spec.Subscription},
+		{Name: envSinkURI, Value: sinkURI},
+		{Name: envTransformerURI, Value: transformerURI},
+		{Name: envConverter, Value: converter},
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: p.Namespace,
+			Name:      deploymentName(p),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(p, sourcesv1alpha1.SchemeGroupVersion.WithKind("PullSubscription")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: p.Spec.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "receive-adapter",
+						Image: receiveAdapterImage,
+						Env:   env,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"testing"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+)
+
+func converterEnv(t *testing.T, p *sourcesv1alpha1.PullSubscription) string {
+	t.Helper()
+	d := makeDeployment(p, "", "")
+	for _, e := range d.Spec.Template.Spec.Containers[0].Env {
+		if e.Name == envConverter {
+			return e.Value
+		}
+	}
+	t.Fatalf("no %s env var set on receive adapter container", envConverter)
+	return ""
+}
+
+func TestMakeDeploymentConverterEnv(t *testing.T) {
+	if got, want := converterEnv(t, &sourcesv1alpha1.PullSubscription{}), "raw_pubsub"; got != want {
+		t.Errorf("unset Converter: env = %q, want %q", got, want)
+	}
+
+	p := &sourcesv1alpha1.PullSubscription{
+		Spec: sourcesv1alpha1.PullSubscriptionSpec{Converter: "push"},
+	}
+	if got, want := converterEnv(t, p), "push"; got != want {
+		t.Errorf("Converter: \"push\": env = %q, want %q", got, want)
+	}
+}
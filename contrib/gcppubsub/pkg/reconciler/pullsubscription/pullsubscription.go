@@ -0,0 +1,268 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pullsubscription reconciles the PullSubscription resource: it creates the GCP PubSub
+// Subscription it describes and the receive adapter Deployment that pulls from it, and tears the
+// Subscription down again on delete according to SubscriptionReclaimPolicy.
+package pullsubscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/pubsubops"
+)
+
+// finalizerName is added to a PullSubscription so that its GCP PubSub Subscription can be torn
+// down (per SubscriptionReclaimPolicy) before the Kubernetes object is actually removed.
+const finalizerName = "pullsubscriptions.sources.eventing.knative.dev"
+
+// CreatePubSubClientFn builds a pubsubops.Interface authenticated for p's GcpCredsSecret.
+type CreatePubSubClientFn func(ctx context.Context, c client.Client, p *sourcesv1alpha1.PullSubscription) (pubsubops.Interface, error)
+
+// DefaultCreatePubSubClient is the production CreatePubSubClientFn.
+func DefaultCreatePubSubClient(ctx context.Context, c client.Client, p *sourcesv1alpha1.PullSubscription) (pubsubops.Interface, error) {
+	return pubsubops.NewFromSecret(ctx, c, p.Namespace, p.Spec.GcpCredsSecret, p.Spec.GoogleCloudProject)
+}
+
+// Reconciler reconciles a PullSubscription.
+type Reconciler struct {
+	Client                 client.Client
+	CreatePubSubClient     CreatePubSubClientFn
+	ResolveObjectReference ObjectReferenceResolverFn
+}
+
+// Reconcile implements controller-runtime's reconcile.Reconciler.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var p sourcesv1alpha1.PullSubscription
+	if err := r.Client.Get(ctx, req.NamespacedName, &p); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !p.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &p)
+	}
+
+	if !containsString(p.Finalizers, finalizerName) {
+		p.Finalizers = append(p.Finalizers, finalizerName)
+		if err := r.Client.Update(ctx, &p); err != nil {
+			return ctrl.Result{}, fmt.Errorf("adding finalizer: %w", err)
+		}
+	}
+
+	p.Status.InitializeConditions()
+	reconcileErr := r.reconcile(ctx, &p)
+
+	if err := r.Client.Update(ctx, &p); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating spec: %w", err)
+	}
+	if err := r.Client.Status().Update(ctx, &p); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating status: %w", err)
+	}
+	return ctrl.Result{}, reconcileErr
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, p *sourcesv1alpha1.PullSubscription) error {
+	ops, err := r.CreatePubSubClient(ctx, r.Client, p)
+	if err != nil {
+		p.Status.MarkNotSubscribed("ClientCreationFailed", "%v", err)
+		p.Status.MarkNotDeployed("ClientCreationFailed", "%v", err)
+		return err
+	}
+
+	if err := r.reconcileSubscription(ctx, ops, p); err != nil {
+		return err
+	}
+
+	sinkURI, err := r.ResolveObjectReference(ctx, r.Client, p.Namespace, p.Spec.Sink)
+	if err != nil {
+		p.Status.MarkNoSink("SinkNotResolved", "%v", err)
+		p.Status.MarkNotDeployed("SinkNotResolved", "%v", err)
+		return err
+	}
+	p.Status.MarkSink(sinkURI)
+
+	transformerURI, err := r.ResolveObjectReference(ctx, r.Client, p.Namespace, p.Spec.Transformer)
+	if err != nil {
+		p.Status.MarkNoTransformer("TransformerNotResolved", "%v", err)
+		p.Status.MarkNotDeployed("TransformerNotResolved", "%v", err)
+		return err
+	}
+	p.Status.MarkTransformer(transformerURI)
+
+	if err := r.reconcileDeployment(ctx, p, sinkURI, transformerURI); err != nil {
+		p.Status.MarkNotDeployed("DeploymentReconcileFailed", "%v", err)
+		return err
+	}
+	p.Status.MarkDeployed()
+	return nil
+}
+
+// reconcileSubscription ensures p's GCP PubSub Subscription exists and that its delivery settings
+// match p.Spec.SubscriptionPolicy, reconciling drift on every call rather than only on creation.
+func (r *Reconciler) reconcileSubscription(ctx context.Context, ops pubsubops.Interface, p *sourcesv1alpha1.PullSubscription) error {
+	subscriptionID := p.Spec.Subscription
+	if subscriptionID == "" {
+		subscriptionID = p.Namespace + "-" + p.Name
+		p.Spec.Subscription = subscriptionID
+	}
+
+	cfg := subscriptionConfigFor(p)
+	wantsDeadLetter := p.Spec.SubscriptionPolicy != nil && p.Spec.SubscriptionPolicy.DeadLetterTopic != nil
+
+	exists, err := ops.SubscriptionExists(ctx, subscriptionID)
+	if err != nil {
+		p.Status.MarkNotSubscribed("SubscriptionVerificationFailed", "%v", err)
+		return err
+	}
+
+	if !exists {
+		err = ops.CreateSubscription(ctx, subscriptionID, p.Spec.Topic, cfg)
+	} else {
+		err = ops.UpdateSubscription(ctx, subscriptionID, cfg)
+	}
+	if err != nil {
+		p.Status.MarkNotSubscribed("SubscriptionReconcileFailed", "%v", err)
+		if wantsDeadLetter {
+			p.Status.MarkNoDeadLetterTopic("SubscriptionReconcileFailed", "%v", err)
+		}
+		return err
+	}
+
+	p.Status.MarkSubscribed()
+	if wantsDeadLetter {
+		p.Status.MarkDeadLetterTopicReady()
+	}
+	return nil
+}
+
+// subscriptionConfigFor translates p.Spec.SubscriptionPolicy into the pubsubops.SubscriptionConfig
+// that reconcileSubscription applies to the underlying GCP PubSub Subscription. A nil
+// SubscriptionPolicy maps to the zero SubscriptionConfig, i.e. GCP PubSub's own defaults.
+func subscriptionConfigFor(p *sourcesv1alpha1.PullSubscription) pubsubops.SubscriptionConfig {
+	policy := p.Spec.SubscriptionPolicy
+	if policy == nil {
+		return pubsubops.SubscriptionConfig{}
+	}
+
+	var cfg pubsubops.SubscriptionConfig
+	if policy.AckDeadlineSeconds != nil {
+		cfg.AckDeadline = time.Duration(*policy.AckDeadlineSeconds) * time.Second
+	}
+	cfg.RetainAckedMessages = policy.RetainAckedMessages
+	if policy.MessageRetentionDuration != nil {
+		cfg.MessageRetentionDuration = policy.MessageRetentionDuration.Duration
+	}
+	if rp := policy.RetryPolicy; rp != nil {
+		if rp.MinimumBackoff != nil {
+			cfg.MinimumBackoff = rp.MinimumBackoff.Duration
+		}
+		if rp.MaximumBackoff != nil {
+			cfg.MaximumBackoff = rp.MaximumBackoff.Duration
+		}
+	}
+	if dlt := policy.DeadLetterTopic; dlt != nil {
+		project := dlt.Project
+		if project == "" {
+			project = p.Spec.GoogleCloudProject
+		}
+		cfg.DeadLetterTopic = fmt.Sprintf("projects/%s/topics/%s", project, dlt.Topic)
+	}
+	if policy.MaxDeliveryAttempts != nil {
+		cfg.MaxDeliveryAttempts = *policy.MaxDeliveryAttempts
+	}
+	return cfg
+}
+
+func (r *Reconciler) reconcileDeployment(ctx context.Context, p *sourcesv1alpha1.PullSubscription, sinkURI, transformerURI string) error {
+	want := makeDeployment(p, sinkURI, transformerURI)
+
+	var got appsv1.Deployment
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(want), &got)
+	switch {
+	case apierrs.IsNotFound(err):
+		return r.Client.Create(ctx, want)
+	case err != nil:
+		return fmt.Errorf("getting receive adapter deployment: %w", err)
+	}
+
+	got.Spec = want.Spec
+	return r.Client.Update(ctx, &got)
+}
+
+// finalize tears down p's GCP PubSub Subscription, unless SubscriptionReclaimPolicy says to
+// retain it, then removes finalizerName so deletion can proceed.
+func (r *Reconciler) finalize(ctx context.Context, p *sourcesv1alpha1.PullSubscription) error {
+	if !containsString(p.Finalizers, finalizerName) {
+		return nil
+	}
+
+	if p.Spec.SubscriptionReclaimPolicy != sourcesv1alpha1.SubscriptionReclaimRetain {
+		// p.Spec.Subscription is normally persisted by reconcileSubscription as soon as it's
+		// derived, but fall back to recomputing it here in case the object is deleted before a
+		// successful reconcile ever wrote it back, so we don't leak the Subscription GCP would
+		// otherwise have created under this deterministic name.
+		subscriptionID := p.Spec.Subscription
+		if subscriptionID == "" {
+			subscriptionID = p.Namespace + "-" + p.Name
+		}
+		ops, err := r.CreatePubSubClient(ctx, r.Client, p)
+		if err != nil {
+			return fmt.Errorf("creating PubSub client to delete subscription: %w", err)
+		}
+		exists, err := ops.SubscriptionExists(ctx, subscriptionID)
+		if err != nil {
+			return fmt.Errorf("checking subscription %q exists: %w", subscriptionID, err)
+		}
+		if exists {
+			if err := ops.DeleteSubscription(ctx, subscriptionID); err != nil {
+				return fmt.Errorf("deleting subscription %q: %w", subscriptionID, err)
+			}
+		}
+	}
+
+	p.Finalizers = removeString(p.Finalizers, finalizerName)
+	return r.Client.Update(ctx, p)
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(ss []string, s string) []string {
+	out := ss[:0]
+	for _, v := range ss {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
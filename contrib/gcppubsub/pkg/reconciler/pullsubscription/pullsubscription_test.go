@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/pubsubops"
+)
+
+func TestSubscriptionConfigFor(t *testing.T) {
+	ackDeadline := int64(30)
+	maxAttempts := int32(7)
+
+	tests := []struct {
+		name string
+		p    *sourcesv1alpha1.PullSubscription
+		want pubsubops.SubscriptionConfig
+	}{{
+		name: "no policy maps to zero config",
+		p:    &sourcesv1alpha1.PullSubscription{},
+		want: pubsubops.SubscriptionConfig{},
+	}, {
+		name: "policy fields translate directly",
+		p: &sourcesv1alpha1.PullSubscription{
+			Spec: sourcesv1alpha1.PullSubscriptionSpec{
+				GoogleCloudProject: "my-project",
+				SubscriptionPolicy: &sourcesv1alpha1.SubscriptionPolicy{
+					AckDeadlineSeconds:       &ackDeadline,
+					RetainAckedMessages:      true,
+					MessageRetentionDuration: &metav1.Duration{Duration: 48 * time.Hour},
+					RetryPolicy: &sourcesv1alpha1.RetryPolicy{
+						MinimumBackoff: &metav1.Duration{Duration: 5 * time.Second},
+						MaximumBackoff: &metav1.Duration{Duration: 30 * time.Second},
+					},
+					DeadLetterTopic:     &sourcesv1alpha1.PubSubTopicReference{Topic: "dead-letters"},
+					MaxDeliveryAttempts: &maxAttempts,
+				},
+			},
+		},
+		want: pubsubops.SubscriptionConfig{
+			AckDeadline:              30 * time.Second,
+			RetainAckedMessages:      true,
+			MessageRetentionDuration: 48 * time.Hour,
+			MinimumBackoff:           5 * time.Second,
+			MaximumBackoff:           30 * time.Second,
+			DeadLetterTopic:          "projects/my-project/topics/dead-letters",
+			MaxDeliveryAttempts:      7,
+		},
+	}, {
+		name: "dead letter topic's own project overrides GoogleCloudProject",
+		p: &sourcesv1alpha1.PullSubscription{
+			Spec: sourcesv1alpha1.PullSubscriptionSpec{
+				GoogleCloudProject: "my-project",
+				SubscriptionPolicy: &sourcesv1alpha1.SubscriptionPolicy{
+					DeadLetterTopic: &sourcesv1alpha1.PubSubTopicReference{
+						Project: "other-project",
+						Topic:   "dead-letters",
+					},
+				},
+			},
+		},
+		want: pubsubops.SubscriptionConfig{
+			DeadLetterTopic: "projects/other-project/topics/dead-letters",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := subscriptionConfigFor(test.p)
+			if got != test.want {
+				t.Errorf("subscriptionConfigFor() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
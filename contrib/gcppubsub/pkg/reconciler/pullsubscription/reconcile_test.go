@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/pubsubops"
+)
+
+// fakeOps is a stub pubsubops.Interface that tracks Subscription existence and calls in memory,
+// so Reconciler.Reconcile can be exercised without talking to GCP.
+type fakeOps struct {
+	subscriptions map[string]bool
+	updated       []string
+	deleted       []string
+}
+
+func newFakeOps(existing ...string) *fakeOps {
+	f := &fakeOps{subscriptions: map[string]bool{}}
+	for _, s := range existing {
+		f.subscriptions[s] = true
+	}
+	return f
+}
+
+func (f *fakeOps) TopicExists(ctx context.Context, topicID string) (bool, error) { return true, nil }
+func (f *fakeOps) CreateTopic(ctx context.Context, topicID string) error         { return nil }
+func (f *fakeOps) AddTopicIAMPolicyBinding(ctx context.Context, topicID, role, member string) error {
+	return nil
+}
+
+func (f *fakeOps) SubscriptionExists(ctx context.Context, subscriptionID string) (bool, error) {
+	return f.subscriptions[subscriptionID], nil
+}
+
+func (f *fakeOps) CreateSubscription(ctx context.Context, subscriptionID, topicID string, cfg pubsubops.SubscriptionConfig) error {
+	f.subscriptions[subscriptionID] = true
+	return nil
+}
+
+func (f *fakeOps) UpdateSubscription(ctx context.Context, subscriptionID string, cfg pubsubops.SubscriptionConfig) error {
+	f.updated = append(f.updated, subscriptionID)
+	return nil
+}
+
+func (f *fakeOps) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	delete(f.subscriptions, subscriptionID)
+	f.deleted = append(f.deleted, subscriptionID)
+	return nil
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := sourcesv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("registering sources/v1alpha1: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("registering apps/v1: %v", err)
+	}
+	return scheme
+}
+
+func newTestReconciler(t *testing.T, ops *fakeOps, objs ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).Build()
+	return &Reconciler{
+		Client: c,
+		CreatePubSubClient: func(ctx context.Context, _ client.Client, _ *sourcesv1alpha1.PullSubscription) (pubsubops.Interface, error) {
+			return ops, nil
+		},
+		ResolveObjectReference: func(ctx context.Context, _ client.Client, _ string, _ *corev1.ObjectReference) (string, error) {
+			return "http://sink.example", nil
+		},
+	}, c
+}
+
+func TestReconcileDerivesAndPersistsSubscriptionName(t *testing.T) {
+	p := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ps"},
+		Spec:       sourcesv1alpha1.PullSubscriptionSpec{Topic: "my-topic"},
+	}
+	ops := newFakeOps()
+	r, c := newTestReconciler(t, ops, p)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(p)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	var got sourcesv1alpha1.PullSubscription
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(p), &got); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if want := "ns-ps"; got.Spec.Subscription != want {
+		t.Errorf("Spec.Subscription = %q, want %q (derived name must be persisted)", got.Spec.Subscription, want)
+	}
+	if !ops.subscriptions["ns-ps"] {
+		t.Error("ns-ps was not created against the fake PubSub client")
+	}
+}
+
+func TestReconcileUpdatesExistingSubscription(t *testing.T) {
+	p := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ps"},
+		Spec:       sourcesv1alpha1.PullSubscriptionSpec{Topic: "my-topic", Subscription: "already-named"},
+	}
+	ops := newFakeOps("already-named")
+	r, _ := newTestReconciler(t, ops, p)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(p)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if len(ops.updated) != 1 || ops.updated[0] != "already-named" {
+		t.Errorf("updated = %v, want a single update of %q", ops.updated, "already-named")
+	}
+}
+
+func TestFinalizeDeletesDerivedSubscriptionNeverPersisted(t *testing.T) {
+	now := metav1.NewTime(time.Unix(0, 0))
+	p := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              "ps",
+			Finalizers:        []string{finalizerName},
+			DeletionTimestamp: &now,
+		},
+		Spec: sourcesv1alpha1.PullSubscriptionSpec{Topic: "my-topic"},
+	}
+	ops := newFakeOps("ns-ps")
+	r, c := newTestReconciler(t, ops, p)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(p)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if len(ops.deleted) != 1 || ops.deleted[0] != "ns-ps" {
+		t.Errorf("deleted = %v, want a single delete of the deterministically-derived name %q", ops.deleted, "ns-ps")
+	}
+
+	var got sourcesv1alpha1.PullSubscription
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(p), &got); err == nil {
+		if containsString(got.Finalizers, finalizerName) {
+			t.Errorf("Finalizers = %v, want %q removed", got.Finalizers, finalizerName)
+		}
+	}
+}
+
+func TestFinalizeRetainsSubscriptionWhenReclaimPolicyIsRetain(t *testing.T) {
+	now := metav1.NewTime(time.Unix(0, 0))
+	p := &sourcesv1alpha1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              "ps",
+			Finalizers:        []string{finalizerName},
+			DeletionTimestamp: &now,
+		},
+		Spec: sourcesv1alpha1.PullSubscriptionSpec{
+			Topic:                     "my-topic",
+			Subscription:              "ns-ps",
+			SubscriptionReclaimPolicy: sourcesv1alpha1.SubscriptionReclaimRetain,
+		},
+	}
+	ops := newFakeOps("ns-ps")
+	r, _ := newTestReconciler(t, ops, p)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(p)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if len(ops.deleted) != 0 {
+		t.Errorf("deleted = %v, want no deletes when SubscriptionReclaimPolicy is Retain", ops.deleted)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectReferenceResolverFn resolves ref (a Sink or Transformer) to the URI it currently
+// addresses. It is a field on Reconciler (rather than a free function) so that tests can stub it
+// without needing a live cluster.
+type ObjectReferenceResolverFn func(ctx context.Context, c client.Client, namespace string, ref *corev1.ObjectReference) (string, error)
+
+// DefaultResolveObjectReference resolves ref by fetching it as an Addressable duck type and
+// reading status.address.url, the same shape every Addressable (Channel, Service, Broker, ...)
+// reports its endpoint at.
+func DefaultResolveObjectReference(ctx context.Context, c client.Client, namespace string, ref *corev1.ObjectReference) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	key := client.ObjectKey{Namespace: namespace, Name: ref.Name}
+	if err := c.Get(ctx, key, u); err != nil {
+		return "", fmt.Errorf("getting addressable %s %q: %w", ref.Kind, ref.Name, err)
+	}
+
+	url, found, err := unstructured.NestedString(u.Object, "status", "address", "url")
+	if err != nil {
+		return "", fmt.Errorf("reading status.address.url from %s %q: %w", ref.Kind, ref.Name, err)
+	}
+	if !found || url == "" {
+		return "", fmt.Errorf("%s %q has not resolved an address yet", ref.Kind, ref.Name)
+	}
+	return url, nil
+}
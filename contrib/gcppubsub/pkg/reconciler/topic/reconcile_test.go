@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topic
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/pubsubops"
+)
+
+// fakeOps is a stub pubsubops.Interface that tracks Topic existence and IAM bindings in memory,
+// so Reconciler.Reconcile can be exercised without talking to GCP.
+type fakeOps struct {
+	topics      map[string]bool
+	bindingsFor map[string]string
+	existsErr   error
+}
+
+func newFakeOps(existing ...string) *fakeOps {
+	f := &fakeOps{topics: map[string]bool{}, bindingsFor: map[string]string{}}
+	for _, topicID := range existing {
+		f.topics[topicID] = true
+	}
+	return f
+}
+
+func (f *fakeOps) TopicExists(ctx context.Context, topicID string) (bool, error) {
+	return f.topics[topicID], f.existsErr
+}
+
+func (f *fakeOps) CreateTopic(ctx context.Context, topicID string) error {
+	f.topics[topicID] = true
+	return nil
+}
+
+func (f *fakeOps) AddTopicIAMPolicyBinding(ctx context.Context, topicID, role, member string) error {
+	f.bindingsFor[topicID] = member
+	return nil
+}
+
+func (f *fakeOps) SubscriptionExists(ctx context.Context, subscriptionID string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeOps) CreateSubscription(ctx context.Context, subscriptionID, topicID string, cfg pubsubops.SubscriptionConfig) error {
+	return nil
+}
+
+func (f *fakeOps) UpdateSubscription(ctx context.Context, subscriptionID string, cfg pubsubops.SubscriptionConfig) error {
+	return nil
+}
+
+func (f *fakeOps) DeleteSubscription(ctx context.Context, subscriptionID string) error { return nil }
+
+func newTestReconciler(t *testing.T, ops *fakeOps, objs ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := sourcesv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("registering sources/v1alpha1: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &Reconciler{
+		Client: c,
+		CreatePubSubClient: func(ctx context.Context, _ client.Client, _ *sourcesv1alpha1.Topic) (pubsubops.Interface, error) {
+			return ops, nil
+		},
+	}, c
+}
+
+func TestReconcileCreatesTopicAndGrantsPublisher(t *testing.T) {
+	topic := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "t"},
+		Spec:       sourcesv1alpha1.TopicSpec{Topic: "my-topic", ServiceAccountName: "publisher-sa"},
+	}
+	ops := newFakeOps()
+	r, c := newTestReconciler(t, ops, topic)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(topic)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if !ops.topics["my-topic"] {
+		t.Error("my-topic was not created against the fake PubSub client")
+	}
+	if got, want := ops.bindingsFor["my-topic"], "serviceAccount:publisher-sa"; got != want {
+		t.Errorf("publisher binding = %q, want %q", got, want)
+	}
+
+	var got sourcesv1alpha1.Topic
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(topic), &got); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if !got.Status.IsReady() {
+		t.Errorf("Status = %+v, want Ready", got.Status)
+	}
+}
+
+func TestReconcileSkipsIAMBindingWithoutServiceAccount(t *testing.T) {
+	topic := &sourcesv1alpha1.Topic{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "t"},
+		Spec:       sourcesv1alpha1.TopicSpec{Topic: "my-topic"},
+	}
+	ops := newFakeOps()
+	r, _ := newTestReconciler(t, ops, topic)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(topic)}); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+
+	if len(ops.bindingsFor) != 0 {
+		t.Errorf("bindingsFor = %v, want no IAM bindings granted", ops.bindingsFor)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topic reconciles the Topic resource: it creates or verifies the underlying GCP PubSub
+// Topic and grants the configured ServiceAccount publisher permission on it.
+package topic
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sourcesv1alpha1 "knative.dev/eventing-contrib/gcppubsub/pkg/apis/sources/v1alpha1"
+	"knative.dev/eventing-contrib/gcppubsub/pkg/reconciler/pubsubops"
+)
+
+// publisherRole is the IAM role granted to Spec.ServiceAccountName so that it can publish to the
+// Topic.
+const publisherRole = "roles/pubsub.publisher"
+
+// CreatePubSubClientFn builds a pubsubops.Interface authenticated for t's GcpCredsSecret. It is a
+// field on Reconciler (rather than a free function) so that tests can stub it with a fake.
+type CreatePubSubClientFn func(ctx context.Context, c client.Client, t *sourcesv1alpha1.Topic) (pubsubops.Interface, error)
+
+// DefaultCreatePubSubClient is the production CreatePubSubClientFn: it reads t.Spec.GcpCredsSecret
+// and authenticates a real GCP PubSub client with it.
+func DefaultCreatePubSubClient(ctx context.Context, c client.Client, t *sourcesv1alpha1.Topic) (pubsubops.Interface, error) {
+	return pubsubops.NewFromSecret(ctx, c, t.Namespace, t.Spec.GcpCredsSecret, t.Spec.GoogleCloudProject)
+}
+
+// Reconciler reconciles a Topic by creating/verifying the GCP PubSub Topic it describes and
+// granting its ServiceAccountName publisher permission on it.
+type Reconciler struct {
+	Client             client.Client
+	CreatePubSubClient CreatePubSubClientFn
+}
+
+// Reconcile implements controller-runtime's reconcile.Reconciler.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var t sourcesv1alpha1.Topic
+	if err := r.Client.Get(ctx, req.NamespacedName, &t); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	t.Status.InitializeConditions()
+	t.Status.ProjectID = t.Spec.GoogleCloudProject
+
+	reconcileErr := r.reconcile(ctx, &t)
+
+	if err := r.Client.Status().Update(ctx, &t); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating status: %w", err)
+	}
+	return ctrl.Result{}, reconcileErr
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, t *sourcesv1alpha1.Topic) error {
+	ops, err := r.CreatePubSubClient(ctx, r.Client, t)
+	if err != nil {
+		t.Status.MarkNoTopic("ClientCreationFailed", "%v", err)
+		t.Status.MarkNoPublisher("ClientCreationFailed", "%v", err)
+		return err
+	}
+
+	exists, err := ops.TopicExists(ctx, t.Spec.Topic)
+	if err != nil {
+		t.Status.MarkNoTopic("TopicVerificationFailed", "%v", err)
+		return err
+	}
+	if !exists {
+		if err := ops.CreateTopic(ctx, t.Spec.Topic); err != nil {
+			t.Status.MarkNoTopic("TopicCreationFailed", "%v", err)
+			return err
+		}
+	}
+	t.Status.MarkTopicReady()
+
+	if t.Spec.ServiceAccountName == "" {
+		t.Status.MarkPublisherReady()
+		return nil
+	}
+
+	member := fmt.Sprintf("serviceAccount:%s", t.Spec.ServiceAccountName)
+	if err := ops.AddTopicIAMPolicyBinding(ctx, t.Spec.Topic, publisherRole, member); err != nil {
+		t.Status.MarkNoPublisher("IAMPolicyBindingFailed", "%v", err)
+		return err
+	}
+	t.Status.MarkPublisherReady()
+	return nil
+}